@@ -0,0 +1,57 @@
+// token: Geliştirme için WebSocket bağlantı tokenı üretir.
+//
+// Kullanıcının veritabanında olması gerekmez; normalde token POST /api/v1/auth/login ile alınır.
+//
+//	AUTH_TOKEN_SECRET=gizli go run ./cmd/token -user ali -role trader -accounts demo
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"v2-trading-bot/internal/adapters/auth"
+	"v2-trading-bot/internal/config"
+	"v2-trading-bot/internal/core/domain"
+)
+
+func main() {
+	user := flag.String("user", "", "kullanıcı kimliği (sub)")
+	role := flag.String("role", string(domain.RoleViewer), "rol (viewer, trader, admin)")
+	accounts := flag.String("accounts", "", "okunabilecek cüzdanlar, virgülle ayrılmış (Örn: demo)")
+	flag.Parse()
+
+	if *user == "" {
+		fmt.Fprintln(os.Stderr, "-user gerekli")
+		os.Exit(2)
+	}
+
+	parsedRole, err := domain.ParseRole(*role)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Ayar hatası: %v\n", err)
+		os.Exit(1)
+	}
+	tokens, err := auth.NewTokenManager(cfg.Auth.TokenSecret, cfg.Auth.TokenTTL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v (AUTH_TOKEN_SECRET ayarlı mı?)\n", err)
+		os.Exit(1)
+	}
+
+	token, _, err := tokens.Issue(domain.User{
+		Username: *user,
+		Role:     parsedRole,
+		Accounts: strings.FieldsFunc(*accounts, func(r rune) bool { return r == ',' || r == ' ' }),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ token üretilemedi: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(token)
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrInvalidCredentials: Kullanıcı adı/şifre ya da API anahtarı hatalı. Hangisinin yanlış
+// olduğu bilerek söylenmez.
+var ErrInvalidCredentials = errors.New("kullanıcı adı, şifre veya API anahtarı hatalı")
+
+// apiKeyPrefix: API anahtarlarını loglarda/ayarlarda tanınır kılar.
+const apiKeyPrefix = "tb_"
+
+// Session: Başarılı girişin sonucu.
+type Session struct {
+	Token     string      `json:"token"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	User      domain.User `json:"user"`
+}
+
+// Authenticator: Kullanıcı adı/şifre veya API anahtarıyla giriş yapar ve JWT üretir.
+type Authenticator struct {
+	users  ports.UserRepository
+	tokens *TokenManager
+
+	// dummyHash: Olmayan kullanıcı için de bcrypt karşılaştırması yapılır; böylece yanıt
+	// süresinden kullanıcı adının var olup olmadığı anlaşılmaz.
+	dummyHash []byte
+}
+
+func NewAuthenticator(users ports.UserRepository, tokens *TokenManager) *Authenticator {
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	return &Authenticator{users: users, tokens: tokens, dummyHash: dummyHash}
+}
+
+// Tokens: Centrifuge bağlantılarını doğrulamak için aynı TokenManager.
+func (a *Authenticator) Tokens() *TokenManager {
+	return a.tokens
+}
+
+// Login: Şifreyi kontrol edip token üretir.
+func (a *Authenticator) Login(username, password string) (*Session, error) {
+	user, err := a.users.GetUser(username)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		bcrypt.CompareHashAndPassword(a.dummyHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return a.session(*user)
+}
+
+// Refresh: Kullanıcıyı veritabanından tekrar okuyup yeni token üretir (rol değiştiyse yenisi geçerli olur).
+func (a *Authenticator) Refresh(username string) (*Session, error) {
+	user, err := a.users.GetUser(username)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a.session(*user)
+}
+
+// VerifyToken: "Authorization: Bearer <token>" ile gelen JWT'yi doğrular.
+func (a *Authenticator) VerifyToken(token string) (*Claims, error) {
+	return a.tokens.Verify(token)
+}
+
+// VerifyAPIKey: "X-API-Key" ile gelen anahtarın sahibini bulur. Rol ve cüzdanlar her
+// istekte veritabanından okunur.
+func (a *Authenticator) VerifyAPIKey(key string) (*Claims, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrInvalidCredentials
+	}
+	user, err := a.users.GetUserByAPIKey(hashAPIKey(key))
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	claims := &Claims{Role: user.Role, Accounts: user.Accounts}
+	claims.Subject = user.Username
+	return claims, nil
+}
+
+// CreateUser: Yeni kullanıcıyı bcrypt'lenmiş şifresiyle kaydeder.
+func (a *Authenticator) CreateUser(username, password string, role domain.Role, accounts []string) (*domain.User, error) {
+	if username == "" {
+		return nil, errors.New("kullanıcı adı boş olamaz")
+	}
+	if len(password) < 8 {
+		return nil, errors.New("şifre en az 8 karakter olmalı")
+	}
+	if _, err := domain.ParseRole(string(role)); err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("şifre hash'lenemedi: %w", err)
+	}
+	user := domain.User{
+		Username:     username,
+		Role:         role,
+		Accounts:     accounts,
+		CreatedAt:    time.Now(),
+		PasswordHash: string(hash),
+	}
+	if err := a.users.CreateUser(user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// EnsureUser: Kullanıcı yoksa oluşturur (ilk admin hesabı için). Varsa dokunmaz.
+func (a *Authenticator) EnsureUser(username, password string, role domain.Role, accounts []string) (created bool, err error) {
+	_, err = a.users.GetUser(username)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return false, err
+	}
+	if _, err := a.CreateUser(username, password, role, accounts); err != nil && !errors.Is(err, domain.ErrUserExists) {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListUsers: Tüm kullanıcılar (hash'ler JSON'a yazılmaz).
+func (a *Authenticator) ListUsers() ([]domain.User, error) {
+	return a.users.ListUsers()
+}
+
+// NewAPIKey: Kullanıcıya yeni bir API anahtarı üretir; varsa eskisi geçersiz olur.
+// Anahtarın kendisi sadece bir kez (burada) görünür, veritabanında özeti tutulur.
+func (a *Authenticator) NewAPIKey(username string) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	key := apiKeyPrefix + hex.EncodeToString(raw)
+	if err := a.users.SetAPIKey(username, hashAPIKey(key)); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (a *Authenticator) session(user domain.User) (*Session, error) {
+	token, expiresAt, err := a.tokens.Issue(user)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{Token: token, ExpiresAt: expiresAt, User: user}, nil
+}
+
+// hashAPIKey: API anahtarları yüksek entropili rastgele değerler olduğu için bcrypt yerine
+// SHA-256 yeterlidir ve özet üzerinden doğrudan arama yapılabilir.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,17 @@
+package auth
+
+import "context"
+
+// claimsKey: Doğrulanmış kullanıcının context anahtarı.
+type claimsKey struct{}
+
+// NewContext: claims'i taşıyan yeni bir context döner.
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext: NewContext ile eklenen claims (yoksa nil).
+func FromContext(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*Claims)
+	return claims
+}
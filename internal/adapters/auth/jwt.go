@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"v2-trading-bot/internal/core/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims: Token içeriği. "sub" kullanıcı kimliğidir (Centrifuge'de UserID olarak görünür).
+type Claims struct {
+	Role domain.Role `json:"role"`
+	// Accounts: Kullanıcının görebileceği paper trading cüzdanları ("wallet:<account>" kanalları).
+	Accounts []string `json:"accounts,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// CanReadAccount: Kullanıcının bu cüzdanın kanalına abone olma yetkisi var mı?
+// Admin tüm cüzdanları görebilir.
+func (c *Claims) CanReadAccount(account string) bool {
+	return c.Role == domain.RoleAdmin || slices.Contains(c.Accounts, account)
+}
+
+// TokenManager: HS256 ile imzalanmış JWT'leri üretir ve doğrular.
+type TokenManager struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewTokenManager: secret boş olamaz. ttl <= 0 ise üretilen tokenlar 24 saat geçerlidir.
+func NewTokenManager(secret string, ttl time.Duration) (*TokenManager, error) {
+	if secret == "" {
+		return nil, errors.New("token secret boş olamaz")
+	}
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &TokenManager{secret: []byte(secret), ttl: ttl}, nil
+}
+
+// Issue: Kullanıcı için imzalı token üretir. Aynı token hem REST (Authorization: Bearer)
+// hem de Centrifuge bağlantısı için kullanılır.
+func (m *TokenManager) Issue(user domain.User) (token string, expiresAt time.Time, err error) {
+	now := time.Now()
+	expiresAt = now.Add(m.ttl)
+	claims := Claims{
+		Role:     user.Role,
+		Accounts: user.Accounts,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.Username,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	return token, expiresAt, err
+}
+
+// Verify: İmzayı, algoritmayı ve süreyi kontrol edip içeriği döner.
+func (m *TokenManager) Verify(token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("geçersiz token: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("geçersiz token: sub yok")
+	}
+	return claims, nil
+}
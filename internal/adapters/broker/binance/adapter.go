@@ -3,75 +3,136 @@ package binance
 import (
 	"encoding/json"
 	"fmt"
-	"log"
-	"strconv"
+	"log/slog"
+	"strings"
 	"time"
+	"v2-trading-bot/internal/adapters/broker/stream"
+	"v2-trading-bot/internal/adapters/metrics"
 	"v2-trading-bot/internal/core/domain"
 	"v2-trading-bot/internal/core/ports"
-
-	"github.com/gorilla/websocket"
+	"v2-trading-bot/internal/logger"
 )
 
 // BinanceAdapter: Dış dünyadan (Binance) veri akısını yöneten yapıdır.
 type BinanceAdapter struct {
 	service ports.TradingService
+	trades  ports.TradeService
+	// url: Akış uç noktası; sembol akışları "<url>/<symbol>@kline_1m" (veya @aggTrade) şeklinde açılır.
+	url string
+
+	// partialKlines: true ise henüz kapanmamış mumlar da ProcessPartialCandle'a iletilir.
+	partialKlines bool
+
+	// recorder: Opsiyonel. Varsa ham mesajlar işlenmeden önce diske yazılır.
+	recorder *stream.Recorder
+	// tracker: Opsiyonel. Varsa bağlantı sağlığı (mesaj hızı, yeniden bağlanma) raporlanır.
+	tracker *stream.Tracker
+
+	log *slog.Logger
 }
 
 // NewBinanceAdapter: Adaptörü oluşturur.
 func NewBinanceAdapter(service ports.TradingService) *BinanceAdapter {
 	return &BinanceAdapter{
 		service: service,
+		url:     "wss://stream.binance.com:9443/ws",
+		log:     logger.For(domain.ExchangeBinance),
+	}
+}
+
+// Name: ports.MarketDataSource
+func (b *BinanceAdapter) Name() string {
+	return domain.ExchangeBinance
+}
+
+// SetPartialKlines: Kapanmamış (in-progress) mumların da işlenip işlenmeyeceğini ayarlar.
+// Connect'ten önce çağrılmalıdır.
+func (b *BinanceAdapter) SetPartialKlines(enabled bool) {
+	b.partialKlines = enabled
+}
+
+// SetRecorder: Ham mesajların kaydedileceği Recorder'ı ayarlar. Connect'ten önce çağrılmalıdır.
+func (b *BinanceAdapter) SetRecorder(recorder *stream.Recorder) {
+	b.recorder = recorder
+}
+
+// SetTracker: Bağlantı sağlığının raporlanacağı Tracker'ı ayarlar. Connect'ten önce çağrılmalıdır.
+func (b *BinanceAdapter) SetTracker(tracker *stream.Tracker) {
+	b.tracker = tracker
+}
+
+// RegisterReplay: Kaydedilmiş Binance akışlarını (kline, aggTrade) bu adaptörün işleyicilerine bağlar.
+// Derinlik akışı REST snapshot'ına bağlı olduğu için replay edilmez.
+func (b *BinanceAdapter) RegisterReplay(replayer *stream.Replayer) {
+	replayer.Handle("binance.kline.", b.handleKline)
+	if b.trades != nil {
+		replayer.Handle("binance.aggTrade.", b.handleAggTrade)
 	}
 }
 
-// Connect: Belirtilen sembol için WebSocket bağlantısını başlatır.
-// Bu fonksiyon "blocking" (kilitleyici) olmamalıdır. o yüzden goroutine içinde çağrılacak.
+// SetTradeService: aggTrade akışından gelen işlemlerin iletileceği servisi ayarlar.
+// ConnectAggTrades'ten önce çağrılmalıdır.
+func (b *BinanceAdapter) SetTradeService(trades ports.TradeService) {
+	b.trades = trades
+}
+
+// Connect: Belirtilen sembol için kline WebSocket bağlantısını başlatır.
+// Bu fonksiyon "blocking" (kilitleyici) dir. o yüzden goroutine içinde çağrılacak.
 func (b *BinanceAdapter) Connect(symbol string) {
-	// 1. Binance WebSocket URL'ini hazırla (küçük harf zorunlu: btcusdt)
+	// Binance WebSocket URL'ini hazırla (küçük harf zorunlu: btcusdt)
 	// format: wss://stream.binance.com:9443/ws/<symbol>@kline_<interval>
-	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@kline_1m", symbol)
-	fmt.Printf("Binance'e bağlanılıyor: %s\n", url)
+	url := fmt.Sprintf("%s/%s@kline_1m", b.url, strings.ToLower(symbol))
+	name := "binance.kline." + domain.NormalizeSymbol(symbol)
+	stream.Listen(stream.Config{URL: url, Name: name, Tracker: b.tracker, Logger: b.log}, b.recorder.Wrap(name, b.handleKline))
+}
 
-	// 2. Bağlantıyı kur (dial)
-	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+// handleKline: Tek bir kline mesajını işler.
+func (b *BinanceAdapter) handleKline(message []byte) {
+	received := time.Now() // receive/parse span'ları bu andan başlar
+
+	// 1. Gelen JSON verisini go struct'ına çeviriyoruz
+	var event BinanceKlineEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		b.log.Warn("kline JSON parse hatası", "err", err)
+		metrics.ParseErrors.WithLabelValues("binance", "kline").Inc()
+		return
+	}
+
+	// 2. Binance formatını -> Bizim Domain formatına (Candle) çevir (Mapping)
+	// Kapanmış mumlar stratejiye gider; kapanmamışlar sadece istenirse canlı akışa.
+	if !event.Kline.IsClosed && !b.partialKlines {
+		return
+	}
+
+	candle, err := event.ToDomain()
 	if err != nil {
-		log.Fatalf("WebSocket bağlantı hatası: %v", err)
+		b.log.Warn("kline çeviri hatası", "err", err)
+		metrics.ParseErrors.WithLabelValues("binance", "kline").Inc()
+		return
 	}
-	// Fonksiyon bitince bağlantıyı kapatmayı unutma.
-	defer conn.Close()
-
-	// 3. Sonsuz döngü: Mesajları Dinle
-	for {
-		// Mesajı oku (readmessage programı durdurur, mesaj gelene kadar bekler)
-		_, message, err := conn.ReadMessage()
-		if err != nil {
-			log.Printf("Okuma hatası: %v", err)
-			// hata varsa 2 saniye bekletiyoruz, döngüye devam et
-			time.Sleep(2 * time.Second)
-			continue
-		}
-
-		// 4. Gelen JSON verisini go struct'ına çeviriyoruz
-		var event BinanceKlineEvent
-		if err := json.Unmarshal(message, &event); err != nil {
-			log.Printf("JSON parse hatası: %v", err)
-			continue
-		}
-
-		// 5. Binance formatını -> Bizim Domain formatına (Candle) çevir (Mapping)
-		// Sadece mum kapanmışsa (IsClosed = true) işleme alacağız
-		if event.Kline.IsClosed {
-			candle, err := event.ToDomain()
-			if err != nil {
-				log.Printf("Çeviri hatası: %v", err)
-				continue
-			}
-
-			// 6. Core katmanını tetikle! (driving port)
-			// adaptor, servise emri veriyor.
-			go b.service.ProcessIncomingCandle(candle)
-		}
 
+	// 3. Core katmanını tetikle! (driving port)
+	// adaptor, servise emri veriyor. Mesajlar akış başına sırayla işlenir: her mum ayrı goroutine'de
+	// işlenirse eski bir canlı mum yenisinin üzerine yazılabilir, yayınların sırası bozulur.
+	b.process(received, candle)
+}
+
+func (b *BinanceAdapter) process(received time.Time, candle domain.Candle) {
+	candle.CorrelationID = domain.NewCorrelationID()
+	log := b.log.With("cid", candle.CorrelationID, "symbol", candle.Symbol, "interval", candle.Interval)
+
+	ctx := stream.StartReceive(domain.ExchangeBinance, received, candle)
+	defer stream.EndReceive(ctx)
+
+	var err error
+	if candle.IsClosed {
+		log.Debug("kapanan mum alındı", "open_time", candle.OpenTime, "close", candle.Close)
+		err = b.service.ProcessIncomingCandle(ctx, candle)
+	} else {
+		err = b.service.ProcessPartialCandle(ctx, candle)
+	}
+	if err != nil {
+		log.Error("mum işlenemedi", "closed", candle.IsClosed, "err", err)
 	}
 }
 
@@ -95,30 +156,50 @@ type BinanceKlineEvent struct {
 		Low         string `json:"l"` // Küçük 'l': Fiyat (String)
 		LastTradeID int64  `json:"L"` // Büyük 'L': İşlem ID (Sayı) - Bunu ekleyince karışıklık biter!
 		// -----------------------------
-
-		Volume   string `json:"v"`
-		IsClosed bool   `json:"x"`
+		// Aynı kural hacimler için de geçerli: Go'nun json paketi büyük/küçük harfe duyarsız
+		// eşleşmeye düşmesin diye 'v'/'V' ve 'q'/'Q' ikisi de tanımlı olmalı.
+
+		FirstTradeID        int64  `json:"f"`
+		TradeCount          int64  `json:"n"`
+		Volume              string `json:"v"` // Base asset hacmi
+		QuoteVolume         string `json:"q"` // Quote asset hacmi
+		TakerBuyBaseVolume  string `json:"V"`
+		TakerBuyQuoteVolume string `json:"Q"`
+		IsClosed            bool   `json:"x"`
 	} `json:"k"`
 }
 
 // ToDomain: Binance formatını bizim temiz Domain Candle yapısına çevirir.
+// Sayıya çevrilemeyen bir alan varsa hata döner (sessizce 0 yazmak yerine).
 func (e *BinanceKlineEvent) ToDomain() (domain.Candle, error) {
 	// String gelen fiyatları Float'a çevirmemiz lazım
-	open, _ := strconv.ParseFloat(e.Kline.Open, 64)
-	closePrice, _ := strconv.ParseFloat(e.Kline.Close, 64)
-	high, _ := strconv.ParseFloat(e.Kline.High, 64)
-	low, _ := strconv.ParseFloat(e.Kline.Low, 64)
-	volume, _ := strconv.ParseFloat(e.Kline.Volume, 64)
-
-	return domain.Candle{
-		Symbol:   e.Symbol,
+	var p stream.FloatParser
+	candle := domain.Candle{
+		Exchange: domain.ExchangeBinance,
+		Symbol:   domain.NormalizeSymbol(e.Symbol),
 		Interval: e.Kline.Interval,
-		Open:     open,
-		Close:    closePrice,
-		High:     high,
-		Low:      low,
-		Volume:   volume,
+
+		Open:   p.Parse("open", e.Kline.Open),
+		Close:  p.Parse("close", e.Kline.Close),
+		High:   p.Parse("high", e.Kline.High),
+		Low:    p.Parse("low", e.Kline.Low),
+		Volume: p.Parse("volume", e.Kline.Volume),
+
+		QuoteVolume:         p.Parse("quote_volume", e.Kline.QuoteVolume),
+		TakerBuyBaseVolume:  p.Parse("taker_buy_base_volume", e.Kline.TakerBuyBaseVolume),
+		TakerBuyQuoteVolume: p.Parse("taker_buy_quote_volume", e.Kline.TakerBuyQuoteVolume),
+		TradeCount:          e.Kline.TradeCount,
+		FirstTradeID:        e.Kline.FirstTradeID,
+		LastTradeID:         e.Kline.LastTradeID,
+
 		// Unix milisaniyeyi -> Go Time nesnesine çevir
-		EventTime: time.UnixMilli(e.EventTime),
-	}, nil
+		// Anahtar olarak mesaj zamanı (E) değil, mumun açılış zamanı (t) kullanılır.
+		OpenTime:  time.UnixMilli(e.Kline.StartTime),
+		CloseTime: time.UnixMilli(e.Kline.EndTime),
+		IsClosed:  e.Kline.IsClosed,
+	}
+	if p.Err != nil {
+		return domain.Candle{}, fmt.Errorf("%s %s kline çevrilemedi: %w", e.Symbol, e.Kline.Interval, p.Err)
+	}
+	return candle, nil
 }
@@ -0,0 +1,67 @@
+package binance
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"v2-trading-bot/internal/adapters/broker/stream/streamtest"
+	"v2-trading-bot/internal/core/domain"
+)
+
+func klineCandle(openTime int64, open, high, low, close float64) domain.Candle {
+	return domain.Candle{
+		Exchange:            domain.ExchangeBinance,
+		Symbol:              "BTCUSDT",
+		Interval:            "1m",
+		Open:                open,
+		High:                high,
+		Low:                 low,
+		Close:               close,
+		Volume:              12.5,
+		QuoteVolume:         874987.5,
+		TradeCount:          1500,
+		TakerBuyBaseVolume:  7.25,
+		TakerBuyQuoteVolume: 507492.75,
+		FirstTradeID:        3620000000,
+		LastTradeID:         3620001499,
+		OpenTime:            time.UnixMilli(openTime),
+		CloseTime:           time.UnixMilli(openTime + 59999),
+		IsClosed:            true,
+	}
+}
+
+// Fixture: kapanmamış mum, kapanmış mum, yarım JSON, geçersiz fiyatlı mum, kapanmış mum.
+func TestConnectReplaysKlineFixture(t *testing.T) {
+	server := streamtest.NewServer(t, "testdata/kline_btcusdt.jsonl")
+	sink := &streamtest.Sink{}
+	adapter := NewBinanceAdapter(sink)
+	adapter.url = server.URL
+	go adapter.Connect("btcusdt")
+
+	sink.WaitClosed(t, 2)
+	// Sunucu ping'i mesajlardan sonra gönderir; pong geldiyse tüm fixture okunmuştur.
+	streamtest.Eventually(t, 2*time.Second, func() bool { return server.Pongs() > 0 }, "ping kontrol frame'ine pong dönmedi")
+
+	want := []domain.Candle{
+		klineCandle(1717999920000, 69980, 70030, 69975.1, 70001),
+		klineCandle(1717999980000, 70001, 70020, 69990, 70015.25),
+	}
+	if got := sink.WaitClosed(t, 2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mumlar beklenenden farklı:\n got %+v\nwant %+v", got, want)
+	}
+	if n := sink.Partial(); n != 0 {
+		t.Errorf("partial kline kapalıyken %d kapanmamış mum iletildi", n)
+	}
+}
+
+func TestConnectForwardsPartialKlines(t *testing.T) {
+	server := streamtest.NewServer(t, "testdata/kline_btcusdt.jsonl")
+	sink := &streamtest.Sink{}
+	adapter := NewBinanceAdapter(sink)
+	adapter.url = server.URL
+	adapter.SetPartialKlines(true)
+	go adapter.Connect("btcusdt")
+
+	sink.WaitClosed(t, 2)
+	streamtest.Eventually(t, 2*time.Second, func() bool { return sink.Partial() == 1 }, "kapanmamış mum iletilmedi")
+}
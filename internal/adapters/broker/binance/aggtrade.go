@@ -0,0 +1,80 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"v2-trading-bot/internal/adapters/broker/stream"
+	"v2-trading-bot/internal/adapters/metrics"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// ConnectAggTrades: Belirtilen sembol için aggTrade (birleştirilmiş işlem) akışını başlatır.
+// Kline'lardan farklı olarak işlemler SIRAYLA işlenmelidir (bar oluşturma sıraya bağlı),
+// o yüzden burada goroutine açılmaz. Blocking'dir, goroutine içinde çağrılmalı.
+func (b *BinanceAdapter) ConnectAggTrades(symbol string) {
+	if b.trades == nil {
+		b.log.Warn("aggTrade servisi ayarlanmamış, akış başlatılmadı", "symbol", symbol)
+		return
+	}
+	url := fmt.Sprintf("%s/%s@aggTrade", b.url, strings.ToLower(symbol))
+	name := "binance.aggTrade." + domain.NormalizeSymbol(symbol)
+	stream.Listen(stream.Config{URL: url, Name: name, Tracker: b.tracker, Logger: b.log}, b.recorder.Wrap(name, b.handleAggTrade))
+}
+
+func (b *BinanceAdapter) handleAggTrade(message []byte) {
+	var event BinanceAggTradeEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		b.log.Warn("aggTrade JSON parse hatası", "err", err)
+		metrics.ParseErrors.WithLabelValues("binance", "aggTrade").Inc()
+		return
+	}
+
+	trade, err := event.ToDomain()
+	if err != nil {
+		b.log.Warn("aggTrade çeviri hatası", "err", err)
+		metrics.ParseErrors.WithLabelValues("binance", "aggTrade").Inc()
+		return
+	}
+
+	if err := b.trades.ProcessIncomingTrade(trade); err != nil {
+		b.log.Error("aggTrade işleme hatası", "symbol", trade.Symbol, "err", err)
+	}
+}
+
+// DTO - aggTrade mesajı
+// Kline'daki gibi büyük/küçük harf çakışmalarına dikkat: 'm' ve 'M' ikisi de tanımlı olmalı.
+type BinanceAggTradeEvent struct {
+	EventType    string `json:"e"`
+	EventTime    int64  `json:"E"`
+	Symbol       string `json:"s"`
+	AggTradeID   int64  `json:"a"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	FirstTradeID int64  `json:"f"`
+	LastTradeID  int64  `json:"l"`
+	TradeTime    int64  `json:"T"`
+	IsBuyerMaker bool   `json:"m"`
+	Ignore       bool   `json:"M"`
+}
+
+// ToDomain: aggTrade mesajını domain Trade yapısına çevirir.
+func (e *BinanceAggTradeEvent) ToDomain() (domain.Trade, error) {
+	var p stream.FloatParser
+	trade := domain.Trade{
+		Exchange:     domain.ExchangeBinance,
+		Symbol:       domain.NormalizeSymbol(e.Symbol),
+		AggTradeID:   e.AggTradeID,
+		Price:        p.Parse("price", e.Price),
+		Quantity:     p.Parse("quantity", e.Quantity),
+		FirstTradeID: e.FirstTradeID,
+		LastTradeID:  e.LastTradeID,
+		Time:         time.UnixMilli(e.TradeTime),
+		IsBuyerMaker: e.IsBuyerMaker,
+	}
+	if p.Err != nil {
+		return domain.Trade{}, fmt.Errorf("%s aggTrade %d çevrilemedi: %w", e.Symbol, e.AggTradeID, p.Err)
+	}
+	return trade, nil
+}
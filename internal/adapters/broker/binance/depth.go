@@ -0,0 +1,390 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"v2-trading-bot/internal/adapters/broker/stream"
+	"v2-trading-bot/internal/adapters/metrics"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+	"v2-trading-bot/internal/logger"
+)
+
+// maxDepthBuffer: Senkronizasyon sırasında tamponlanan en fazla olay sayısı (100ms'de bir ~ 100 saniye).
+const maxDepthBuffer = 1000
+
+// DepthClient: Sembol başına yerel emir defteri tutar.
+// Binance'in önerdiği yöntem:
+//  1. @depth@100ms akışını aç, gelen olayları tamponla.
+//  2. REST'ten snapshot al (lastUpdateId).
+//  3. u <= lastUpdateId olan olayları at; ilk uygulanan olay U <= lastUpdateId+1 <= u olmalı.
+//  4. Sonraki her olayda U == önceki u + 1 olmalı. Boşluk varsa defteri at ve 2. adımdan tekrarla.
+//
+// ports.OrderBookProvider'ı implemente eder.
+type DepthClient struct {
+	publisher       ports.EventBus
+	publishInterval time.Duration
+	restURL         string
+	httpClient      *http.Client
+	tracker         *stream.Tracker
+	log             *slog.Logger
+
+	mu      sync.RWMutex
+	streams map[string]*depthStream
+}
+
+// NewDepthClient: publishInterval, top-of-book yayınları arasındaki en kısa süredir (throttle).
+func NewDepthClient(publisher ports.EventBus, publishInterval time.Duration) *DepthClient {
+	return &DepthClient{
+		publisher:       publisher,
+		publishInterval: publishInterval,
+		restURL:         "https://api.binance.com/api/v3/depth",
+		httpClient:      &http.Client{Timeout: 10 * time.Second},
+		log:             logger.For("depth"),
+		streams:         make(map[string]*depthStream),
+	}
+}
+
+// SetTracker: Bağlantı sağlığının raporlanacağı Tracker'ı ayarlar. Connect'ten önce çağrılmalıdır.
+func (d *DepthClient) SetTracker(tracker *stream.Tracker) {
+	d.tracker = tracker
+}
+
+// Connect: Sembol için derinlik akışını başlatır. Blocking'dir, goroutine içinde çağrılmalı.
+func (d *DepthClient) Connect(symbol string) {
+	symbol = strings.ToUpper(symbol)
+	ds := &depthStream{client: d, symbol: symbol}
+
+	d.mu.Lock()
+	d.streams[symbol] = ds
+	d.mu.Unlock()
+
+	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@depth@100ms", strings.ToLower(symbol))
+	stream.Listen(stream.Config{URL: url, Name: "binance.depth." + symbol, Tracker: d.tracker, Logger: d.log}, ds.handle)
+}
+
+// BestBidAsk: ports.OrderBookProvider
+func (d *DepthClient) BestBidAsk(symbol string) (bid, ask domain.PriceLevel, ok bool) {
+	d.withBook(symbol, func(book *domain.OrderBook) {
+		var okBid, okAsk bool
+		bid, okBid = book.BestBid()
+		ask, okAsk = book.BestAsk()
+		ok = okBid && okAsk
+	})
+	return bid, ask, ok
+}
+
+// DepthAt: ports.OrderBookProvider
+func (d *DepthClient) DepthAt(symbol string, side domain.BookSide, price float64) float64 {
+	var qty float64
+	d.withBook(symbol, func(book *domain.OrderBook) {
+		qty = book.DepthAt(side, price)
+	})
+	return qty
+}
+
+// Imbalance: ports.OrderBookProvider
+func (d *DepthClient) Imbalance(symbol string, levels int) (float64, bool) {
+	var imbalance float64
+	ok := d.withBook(symbol, func(book *domain.OrderBook) {
+		imbalance = book.Imbalance(levels)
+	})
+	return imbalance, ok
+}
+
+// withBook: Senkronize bir defter varsa kilit altında fn'i çalıştırır.
+func (d *DepthClient) withBook(symbol string, fn func(book *domain.OrderBook)) bool {
+	d.mu.RLock()
+	ds := d.streams[strings.ToUpper(symbol)]
+	d.mu.RUnlock()
+	if ds == nil {
+		return false
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if ds.book == nil {
+		return false
+	}
+	fn(ds.book)
+	return true
+}
+
+// depthStream: Tek bir sembolün senkronizasyon durumu.
+type depthStream struct {
+	client *DepthClient
+	symbol string
+
+	mu          sync.Mutex
+	book        *domain.OrderBook // nil ise senkronize değil
+	buffer      []depthEvent      // Snapshot beklenirken gelen olaylar
+	syncing     bool              // Snapshot isteği devam ediyor mu?
+	lastPublish time.Time
+	lastTop     [2]domain.PriceLevel
+	// flush: Throttle süresi içinde tepe değiştiyse, süre dolunca son hali yayınlayacak zamanlayıcı.
+	flush *time.Timer
+}
+
+func (s *depthStream) handle(message []byte) {
+	var raw binanceDepthEvent
+	if err := json.Unmarshal(message, &raw); err != nil {
+		s.client.log.Warn("depth JSON parse hatası", "symbol", s.symbol, "err", err)
+		metrics.ParseErrors.WithLabelValues("binance", "depth").Inc()
+		return
+	}
+	event, err := raw.parse()
+	if err != nil {
+		s.client.log.Warn("depth çeviri hatası", "symbol", s.symbol, "err", err)
+		metrics.ParseErrors.WithLabelValues("binance", "depth").Inc()
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.book == nil {
+		// Snapshot uzun süre alınamazsa tampon şişmesin; eskiler zaten işe yaramaz hale gelir.
+		if len(s.buffer) >= maxDepthBuffer {
+			s.buffer = s.buffer[1:]
+		}
+		s.buffer = append(s.buffer, event)
+		s.startSync()
+		return
+	}
+
+	if !s.applyLocked(event) {
+		s.client.log.Warn("defterde boşluk, yeniden senkronize ediliyor", "symbol", s.symbol,
+			"expected_u", s.book.LastUpdateID+1, "got_u", event.FirstUpdateID)
+		s.book = nil
+		s.buffer = []depthEvent{event}
+		s.startSync()
+		return
+	}
+	s.publishLocked()
+}
+
+// applyLocked: Olayı deftere uygular. Sıra bozuksa (boşluk) false döner.
+func (s *depthStream) applyLocked(e depthEvent) bool {
+	next := s.book.LastUpdateID + 1
+	if e.FinalUpdateID < next {
+		return true // Eski olay, zaten snapshot'ın içinde.
+	}
+	if e.FirstUpdateID > next {
+		return false
+	}
+	s.book.Apply(e.Bids, e.Asks)
+	s.book.LastUpdateID = e.FinalUpdateID
+	return true
+}
+
+// startSync: Arka planda snapshot çeker (zaten çekiliyorsa bir şey yapmaz).
+func (s *depthStream) startSync() {
+	if s.syncing {
+		return
+	}
+	s.syncing = true
+	go s.sync()
+}
+
+func (s *depthStream) sync() {
+	for attempt := 1; ; attempt++ {
+		snapshot, err := s.client.fetchSnapshot(s.symbol)
+		if err != nil {
+			s.client.log.Error("snapshot alınamadı", "symbol", s.symbol, "attempt", attempt, "err", err)
+			time.Sleep(time.Duration(min(attempt, 10)) * time.Second)
+			continue
+		}
+
+		s.mu.Lock()
+		book := domain.NewOrderBook(s.symbol, snapshot.LastUpdateID, snapshot.Bids, snapshot.Asks)
+
+		// Tamponda snapshot'tan DAHA YENİ bir başlangıç varsa snapshot eskidir, tekrar çek.
+		if len(s.buffer) > 0 {
+			last := s.buffer[len(s.buffer)-1]
+			if last.FinalUpdateID > snapshot.LastUpdateID && s.firstRelevant(snapshot.LastUpdateID) == nil {
+				s.mu.Unlock()
+				time.Sleep(time.Second)
+				continue
+			}
+		}
+
+		s.book = book
+		ok := true
+		for _, e := range s.buffer {
+			if ok = s.applyLocked(e); !ok {
+				break
+			}
+		}
+		s.buffer = nil
+		if !ok {
+			// Tampon kendi içinde bile tutarsız; baştan al.
+			s.book = nil
+			s.mu.Unlock()
+			continue
+		}
+
+		s.syncing = false
+		s.client.log.Info("emir defteri senkronize edildi", "symbol", s.symbol, "last_update_id", s.book.LastUpdateID)
+		s.publishLocked()
+		s.mu.Unlock()
+		return
+	}
+}
+
+// firstRelevant: Snapshot'tan sonra uygulanacak ilk olay. U <= lastUpdateId+1 <= u koşulunu sağlamalı.
+func (s *depthStream) firstRelevant(lastUpdateID int64) *depthEvent {
+	for i := range s.buffer {
+		e := &s.buffer[i]
+		if e.FinalUpdateID <= lastUpdateID {
+			continue
+		}
+		if e.FirstUpdateID <= lastUpdateID+1 {
+			return e
+		}
+		return nil
+	}
+	return nil
+}
+
+// publishLocked: Defterin tepesi değiştiyse yayınlar. Throttle süresi dolmadıysa değişiklik atılmaz,
+// süre dolunca (flushLater) defterin o anki tepesi yayınlanır; böylece bir patlamanın ardından
+// ticker kanalında eski fiyat kalmaz.
+func (s *depthStream) publishLocked() {
+	if s.client.publisher == nil || s.book == nil {
+		return
+	}
+	bid, okBid := s.book.BestBid()
+	ask, okAsk := s.book.BestAsk()
+	if !okBid || !okAsk {
+		return
+	}
+	top := [2]domain.PriceLevel{bid, ask}
+	if top == s.lastTop {
+		return
+	}
+	if wait := s.client.publishInterval - time.Since(s.lastPublish); wait > 0 {
+		if s.flush == nil {
+			s.flush = time.AfterFunc(wait, s.flushLater)
+		}
+		return
+	}
+	s.lastTop = top
+	s.lastPublish = time.Now()
+
+	ticker := domain.BookTicker{
+		Symbol:    s.symbol,
+		BidPrice:  bid.Price,
+		BidQty:    bid.Quantity,
+		AskPrice:  ask.Price,
+		AskQty:    ask.Quantity,
+		Spread:    ask.Price - bid.Price,
+		Imbalance: s.book.Imbalance(10),
+		Time:      s.lastPublish,
+	}
+	if err := s.client.publisher.PublishBookTicker(ticker); err != nil {
+		s.client.log.Warn("defter yayını başarısız", "symbol", s.symbol, "err", err)
+	}
+}
+
+// flushLater: Throttle yüzünden bekleyen son değişikliği yayınlar.
+func (s *depthStream) flushLater() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flush = nil
+	s.publishLocked()
+}
+
+type depthSnapshot struct {
+	LastUpdateID int64
+	Bids         []domain.PriceLevel
+	Asks         []domain.PriceLevel
+}
+
+// fetchSnapshot: GET /api/v3/depth?symbol=BTCUSDT&limit=1000
+func (d *DepthClient) fetchSnapshot(symbol string) (*depthSnapshot, error) {
+	resp, err := d.httpClient.Get(fmt.Sprintf("%s?symbol=%s&limit=1000", d.restURL, symbol))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("beklenmeyen HTTP durumu: %s", resp.Status)
+	}
+
+	var raw struct {
+		LastUpdateID int64       `json:"lastUpdateId"`
+		Bids         [][2]string `json:"bids"`
+		Asks         [][2]string `json:"asks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	bids, err := parseLevels(raw.Bids)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseLevels(raw.Asks)
+	if err != nil {
+		return nil, err
+	}
+	return &depthSnapshot{LastUpdateID: raw.LastUpdateID, Bids: bids, Asks: asks}, nil
+}
+
+// DTO - depthUpdate mesajı ('U' ve 'u' ikisi de tanımlı olmalı, bkz. kline notu)
+type binanceDepthEvent struct {
+	EventType     string      `json:"e"`
+	EventTime     int64       `json:"E"`
+	Symbol        string      `json:"s"`
+	FirstUpdateID int64       `json:"U"`
+	FinalUpdateID int64       `json:"u"`
+	Bids          [][2]string `json:"b"`
+	Asks          [][2]string `json:"a"`
+}
+
+type depthEvent struct {
+	FirstUpdateID int64
+	FinalUpdateID int64
+	Bids          []domain.PriceLevel
+	Asks          []domain.PriceLevel
+}
+
+func (e *binanceDepthEvent) parse() (depthEvent, error) {
+	bids, err := parseLevels(e.Bids)
+	if err != nil {
+		return depthEvent{}, err
+	}
+	asks, err := parseLevels(e.Asks)
+	if err != nil {
+		return depthEvent{}, err
+	}
+	return depthEvent{
+		FirstUpdateID: e.FirstUpdateID,
+		FinalUpdateID: e.FinalUpdateID,
+		Bids:          bids,
+		Asks:          asks,
+	}, nil
+}
+
+// parseLevels: [["fiyat","miktar"], ...] -> []PriceLevel
+func parseLevels(raw [][2]string) ([]domain.PriceLevel, error) {
+	levels := make([]domain.PriceLevel, 0, len(raw))
+	for _, l := range raw {
+		price, err := strconv.ParseFloat(l[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("geçersiz fiyat %q: %w", l[0], err)
+		}
+		qty, err := strconv.ParseFloat(l[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("geçersiz miktar %q: %w", l[1], err)
+		}
+		levels = append(levels, domain.PriceLevel{Price: price, Quantity: qty})
+	}
+	return levels, nil
+}
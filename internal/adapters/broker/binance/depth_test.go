@@ -0,0 +1,157 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"v2-trading-bot/internal/adapters/broker/stream/streamtest"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// snapshotServer: Her GET /api/v3/depth isteğine snapshots kanalından gelen sıradaki snapshot'ı döner.
+// Kanala yazılana kadar istek bekler; böylece test, senkronizasyon sırasında olay gönderebilir.
+type snapshotServer struct {
+	snapshots chan string
+	requests  atomic.Int32
+}
+
+func newDepthTestStream(t *testing.T) (*depthStream, *snapshotServer) {
+	t.Helper()
+	ss := &snapshotServer{snapshots: make(chan string, 4)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ss.requests.Add(1)
+		select {
+		case body := <-ss.snapshots:
+			w.Write([]byte(body))
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewDepthClient(nil, 0)
+	client.restURL = srv.URL
+	ds := &depthStream{client: client, symbol: "BTCUSDT"}
+	client.streams["BTCUSDT"] = ds
+	return ds, ss
+}
+
+func snapshot(lastUpdateID int64, bids, asks [][2]string) string {
+	body, _ := json.Marshal(map[string]any{"lastUpdateId": lastUpdateID, "bids": bids, "asks": asks})
+	return string(body)
+}
+
+func depthUpdate(first, final int64, bids, asks [][2]string) []byte {
+	message, _ := json.Marshal(binanceDepthEvent{
+		EventType: "depthUpdate", Symbol: "BTCUSDT", FirstUpdateID: first, FinalUpdateID: final, Bids: bids, Asks: asks,
+	})
+	return message
+}
+
+// synced: Defter senkronize olup son güncelleme id'si lastUpdateID olana kadar bekler.
+func synced(t *testing.T, ds *depthStream, lastUpdateID int64) {
+	t.Helper()
+	streamtest.Eventually(t, 5*time.Second, func() bool {
+		ds.mu.Lock()
+		defer ds.mu.Unlock()
+		return ds.book != nil && !ds.syncing && ds.book.LastUpdateID == lastUpdateID
+	}, fmt.Sprintf("defter %d'e senkronize olmadı", lastUpdateID))
+}
+
+func bestBidAsk(t *testing.T, ds *depthStream) (domain.PriceLevel, domain.PriceLevel) {
+	t.Helper()
+	bid, ask, ok := ds.client.BestBidAsk("BTCUSDT")
+	if !ok {
+		t.Fatal("defter senkronize değil")
+	}
+	return bid, ask
+}
+
+func TestDepthSnapshotAndBufferedEvents(t *testing.T) {
+	ds, ss := newDepthTestStream(t)
+
+	// Snapshot beklenirken gelen olaylar tamponlanır.
+	ds.handle(depthUpdate(90, 95, [][2]string{{"99", "9"}}, nil))                         // Snapshot'tan eski: atılır
+	ds.handle(depthUpdate(99, 102, [][2]string{{"100", "2"}}, [][2]string{{"101", "4"}})) // lastUpdateId'yi kapsar
+	ds.handle(depthUpdate(103, 105, nil, [][2]string{{"101", "0"}, {"102", "3"}}))        // 0 miktar seviyeyi siler
+	ss.snapshots <- snapshot(100, [][2]string{{"100", "1"}, {"98", "1"}}, [][2]string{{"101", "1"}, {"103", "1"}})
+	synced(t, ds, 105)
+
+	bid, ask := bestBidAsk(t, ds)
+	if bid != (domain.PriceLevel{Price: 100, Quantity: 2}) || ask != (domain.PriceLevel{Price: 102, Quantity: 3}) {
+		t.Fatalf("tepe bid=%+v ask=%+v", bid, ask)
+	}
+	if ds.client.DepthAt("BTCUSDT", domain.SideBid, 99) != 0 {
+		t.Fatal("snapshot'tan eski olay uygulanmamalıydı")
+	}
+
+	// Senkronken gelen eski olay yok sayılır, sıradaki uygulanır.
+	ds.handle(depthUpdate(104, 105, [][2]string{{"100", "50"}}, nil))
+	ds.handle(depthUpdate(106, 106, [][2]string{{"100", "0"}}, nil))
+	synced(t, ds, 106)
+	if bid, _ := bestBidAsk(t, ds); bid != (domain.PriceLevel{Price: 98, Quantity: 1}) {
+		t.Fatalf("0 miktarlı seviye silinmeli, tepe bid=%+v", bid)
+	}
+	if n := ss.requests.Load(); n != 1 {
+		t.Fatalf("tek snapshot isteği bekleniyordu, %d", n)
+	}
+}
+
+func TestDepthGapForcesResync(t *testing.T) {
+	ds, ss := newDepthTestStream(t)
+
+	ds.handle(depthUpdate(10, 11, nil, nil))
+	ss.snapshots <- snapshot(10, [][2]string{{"100", "1"}}, [][2]string{{"101", "1"}})
+	synced(t, ds, 11)
+
+	// U != önceki u + 1: defter atılır, yeni snapshot beklenir.
+	ds.handle(depthUpdate(13, 14, [][2]string{{"100", "7"}}, nil))
+	ds.mu.Lock()
+	dropped := ds.book == nil && ds.syncing
+	ds.mu.Unlock()
+	if !dropped {
+		t.Fatal("boşlukta defter atılıp yeniden senkronizasyon başlamalıydı")
+	}
+	if _, _, ok := ds.client.BestBidAsk("BTCUSDT"); ok {
+		t.Fatal("senkronizasyon sırasında defter okunmamalı")
+	}
+
+	ds.handle(depthUpdate(15, 16, [][2]string{{"100", "8"}}, nil))
+	ss.snapshots <- snapshot(13, [][2]string{{"100", "5"}}, [][2]string{{"101", "1"}})
+	synced(t, ds, 16)
+	if bid, _ := bestBidAsk(t, ds); bid.Quantity != 8 {
+		t.Fatalf("tampondaki olaylar yeni snapshot'a uygulanmalı, bid=%+v", bid)
+	}
+	if n := ss.requests.Load(); n != 2 {
+		t.Fatalf("2 snapshot isteği bekleniyordu, %d", n)
+	}
+}
+
+func TestDepthBufferOverflowWhileSyncing(t *testing.T) {
+	ds, ss := newDepthTestStream(t)
+
+	total := int64(maxDepthBuffer + 5)
+	for id := int64(1); id <= total; id++ {
+		ds.handle(depthUpdate(id, id, [][2]string{{"100", fmt.Sprint(id)}}, nil))
+	}
+	ds.mu.Lock()
+	buffered, first := len(ds.buffer), ds.buffer[0].FirstUpdateID
+	ds.mu.Unlock()
+	if buffered != maxDepthBuffer || first != 6 {
+		t.Fatalf("tampon %d olayla sınırlı olmalı ve en eskiler atılmalı: %d olay, ilki %d", maxDepthBuffer, buffered, first)
+	}
+
+	// İlk snapshot atılan olaylardan önceye ait: tamponla birleşemez, tekrar çekilir.
+	ss.snapshots <- snapshot(2, [][2]string{{"100", "1"}}, [][2]string{{"101", "1"}})
+	ss.snapshots <- snapshot(500, [][2]string{{"100", "1"}}, [][2]string{{"101", "1"}})
+	synced(t, ds, total)
+	if bid, _ := bestBidAsk(t, ds); bid.Quantity != float64(total) {
+		t.Fatalf("son olay uygulanmalı, bid=%+v", bid)
+	}
+	if n := ss.requests.Load(); n != 2 {
+		t.Fatalf("eski snapshot'tan sonra tekrar istenmeliydi, %d istek", n)
+	}
+}
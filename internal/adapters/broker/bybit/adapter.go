@@ -0,0 +1,216 @@
+package bybit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"v2-trading-bot/internal/adapters/broker/stream"
+	"v2-trading-bot/internal/adapters/metrics"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+	"v2-trading-bot/internal/logger"
+)
+
+// BybitAdapter: Bybit spot public kline akışını dinler.
+// ports.MarketDataSource'u implemente eder.
+type BybitAdapter struct {
+	service ports.TradingService
+	url     string
+	// pingInterval: Bağlantının açık kalması için uygulama seviyesi ping aralığı.
+	pingInterval time.Duration
+
+	// partialKlines: true ise henüz kapanmamış mumlar da ProcessPartialCandle'a iletilir.
+	partialKlines bool
+
+	// recorder: Opsiyonel. Varsa ham mesajlar işlenmeden önce diske yazılır.
+	recorder *stream.Recorder
+	// tracker: Opsiyonel. Varsa bağlantı sağlığı (mesaj hızı, yeniden bağlanma) raporlanır.
+	tracker *stream.Tracker
+
+	log *slog.Logger
+}
+
+// NewBybitAdapter: Adaptörü oluşturur.
+func NewBybitAdapter(service ports.TradingService) *BybitAdapter {
+	return &BybitAdapter{
+		service:      service,
+		url:          "wss://stream.bybit.com/v5/public/spot",
+		pingInterval: 20 * time.Second,
+		log:          logger.For(domain.ExchangeBybit),
+	}
+}
+
+// Name: ports.MarketDataSource
+func (b *BybitAdapter) Name() string {
+	return domain.ExchangeBybit
+}
+
+// SetPartialKlines: Kapanmamış (in-progress) mumların da işlenip işlenmeyeceğini ayarlar.
+func (b *BybitAdapter) SetPartialKlines(enabled bool) {
+	b.partialKlines = enabled
+}
+
+// SetRecorder: Ham mesajların kaydedileceği Recorder'ı ayarlar. Connect'ten önce çağrılmalıdır.
+func (b *BybitAdapter) SetRecorder(recorder *stream.Recorder) {
+	b.recorder = recorder
+}
+
+// SetTracker: Bağlantı sağlığının raporlanacağı Tracker'ı ayarlar. Connect'ten önce çağrılmalıdır.
+func (b *BybitAdapter) SetTracker(tracker *stream.Tracker) {
+	b.tracker = tracker
+}
+
+// RegisterReplay: Kaydedilmiş bybit akışlarını bu adaptörün işleyicisine bağlar.
+func (b *BybitAdapter) RegisterReplay(replayer *stream.Replayer) {
+	replayer.Handle("bybit.kline.", b.handleMessage)
+}
+
+// Connect: ports.MarketDataSource. Bybit'te abonelik bağlantıdan sonra mesajla yapılır
+// ve bağlantı açık kalsın diye 20 saniyede bir ping gönderilmelidir.
+func (b *BybitAdapter) Connect(symbol string) {
+	sub, _ := json.Marshal(map[string]any{
+		"op":   "subscribe",
+		"args": []string{"kline.1." + domain.NormalizeSymbol(symbol)},
+	})
+
+	name := "bybit.kline." + domain.NormalizeSymbol(symbol)
+	stream.Listen(stream.Config{
+		URL:          b.url,
+		Name:         name,
+		Tracker:      b.tracker,
+		Logger:       b.log,
+		Subscribe:    [][]byte{sub},
+		PingInterval: b.pingInterval,
+		PingMessage:  []byte(`{"op":"ping"}`),
+	}, b.recorder.Wrap(name, b.handleMessage))
+}
+
+func (b *BybitAdapter) handleMessage(message []byte) {
+	received := time.Now() // receive/parse span'ları bu andan başlar
+
+	var event BybitKlineEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		b.log.Warn("kline JSON parse hatası", "err", err)
+		metrics.ParseErrors.WithLabelValues("bybit", "kline").Inc()
+		return
+	}
+	// Abonelik cevabı, pong vb. mesajlarda topic olmaz.
+	if !strings.HasPrefix(event.Topic, "kline.") {
+		return
+	}
+
+	candles, err := event.ToDomain()
+	if err != nil {
+		b.log.Warn("kline çeviri hatası", "err", err)
+		metrics.ParseErrors.WithLabelValues("bybit", "kline").Inc()
+		return
+	}
+	for _, candle := range candles {
+		if !candle.IsClosed && !b.partialKlines {
+			continue
+		}
+		// Akış başına sırayla işlenir; ayrı goroutine'ler eski canlı mumu yenisinin üzerine yazabilirdi.
+		b.process(received, candle)
+	}
+}
+
+func (b *BybitAdapter) process(received time.Time, candle domain.Candle) {
+	candle.CorrelationID = domain.NewCorrelationID()
+	log := b.log.With("cid", candle.CorrelationID, "symbol", candle.Symbol, "interval", candle.Interval)
+
+	ctx := stream.StartReceive(domain.ExchangeBybit, received, candle)
+	defer stream.EndReceive(ctx)
+
+	var err error
+	if candle.IsClosed {
+		log.Debug("kapanan mum alındı", "open_time", candle.OpenTime, "close", candle.Close)
+		err = b.service.ProcessIncomingCandle(ctx, candle)
+	} else {
+		err = b.service.ProcessPartialCandle(ctx, candle)
+	}
+	if err != nil {
+		log.Error("mum işlenemedi", "closed", candle.IsClosed, "err", err)
+	}
+}
+
+// DTO - Bybit v5 kline mesajı
+// {"topic":"kline.1.BTCUSDT","type":"snapshot","ts":1672324988882,"data":[{...}]}
+type BybitKlineEvent struct {
+	Topic string `json:"topic"`
+	Type  string `json:"type"`
+	TS    int64  `json:"ts"`
+	Data  []struct {
+		Start     int64  `json:"start"`
+		End       int64  `json:"end"`
+		Interval  string `json:"interval"`
+		Open      string `json:"open"`
+		Close     string `json:"close"`
+		High      string `json:"high"`
+		Low       string `json:"low"`
+		Volume    string `json:"volume"`
+		Turnover  string `json:"turnover"`
+		Confirm   bool   `json:"confirm"`
+		Timestamp int64  `json:"timestamp"`
+	} `json:"data"`
+}
+
+// ToDomain: Bybit kline'larını domain Candle'a çevirir. Sembol topic'ten okunur ("kline.1.BTCUSDT").
+func (e *BybitKlineEvent) ToDomain() ([]domain.Candle, error) {
+	parts := strings.Split(e.Topic, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("beklenmeyen topic %q", e.Topic)
+	}
+	symbol := domain.NormalizeSymbol(parts[2])
+
+	candles := make([]domain.Candle, 0, len(e.Data))
+	for _, k := range e.Data {
+		interval, err := normalizeInterval(k.Interval)
+		if err != nil {
+			return nil, err
+		}
+
+		var p stream.FloatParser
+		candle := domain.Candle{
+			Exchange:    domain.ExchangeBybit,
+			Symbol:      symbol,
+			Interval:    interval,
+			Open:        p.Parse("open", k.Open),
+			Close:       p.Parse("close", k.Close),
+			High:        p.Parse("high", k.High),
+			Low:         p.Parse("low", k.Low),
+			Volume:      p.Parse("volume", k.Volume),
+			QuoteVolume: p.Parse("turnover", k.Turnover),
+			OpenTime:    time.UnixMilli(k.Start),
+			CloseTime:   time.UnixMilli(k.End),
+			IsClosed:    k.Confirm,
+		}
+		if p.Err != nil {
+			return nil, fmt.Errorf("%s kline çevrilemedi: %w", symbol, p.Err)
+		}
+		candles = append(candles, candle)
+	}
+	return candles, nil
+}
+
+// normalizeInterval: Bybit interval'ini ("1", "60", "D") Binance formatına ("1m", "1h", "1d") çevirir.
+func normalizeInterval(raw string) (string, error) {
+	switch raw {
+	case "D":
+		return "1d", nil
+	case "W":
+		return "1w", nil
+	case "M":
+		return "1M", nil
+	}
+	minutes, err := strconv.Atoi(raw)
+	if err != nil {
+		return "", fmt.Errorf("bilinmeyen bybit interval %q", raw)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60), nil
+	}
+	return fmt.Sprintf("%dm", minutes), nil
+}
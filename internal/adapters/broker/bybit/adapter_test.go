@@ -0,0 +1,65 @@
+package bybit
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+	"v2-trading-bot/internal/adapters/broker/stream/streamtest"
+	"v2-trading-bot/internal/core/domain"
+)
+
+func klineCandle(openTime int64, open, high, low, close float64) domain.Candle {
+	return domain.Candle{
+		Exchange:    domain.ExchangeBybit,
+		Symbol:      "BTCUSDT",
+		Interval:    "1m",
+		Open:        open,
+		High:        high,
+		Low:         low,
+		Close:       close,
+		Volume:      3.2154,
+		QuoteVolume: 225000.12,
+		OpenTime:    time.UnixMilli(openTime),
+		CloseTime:   time.UnixMilli(openTime + 59999),
+		IsClosed:    true,
+	}
+}
+
+// Fixture: abonelik cevabı, kapanmamış mum, kapanmış mum, pong, yarım JSON,
+// bilinmeyen interval'li mum, kapanmış mum.
+func TestConnectReplaysKlineFixture(t *testing.T) {
+	server := streamtest.NewServer(t, "testdata/kline_btcusdt.jsonl")
+	server.Reply = func(message []byte) []byte {
+		if string(message) == `{"op":"ping"}` {
+			return []byte(`{"success":true,"ret_msg":"pong","conn_id":"test","op":"ping"}`)
+		}
+		return nil
+	}
+	sink := &streamtest.Sink{}
+	adapter := NewBybitAdapter(sink)
+	adapter.url = server.URL
+	adapter.pingInterval = 20 * time.Millisecond
+	go adapter.Connect("btcusdt")
+
+	sink.WaitClosed(t, 2)
+	streamtest.Eventually(t, 2*time.Second, func() bool {
+		return slices.Contains(server.Received(), `{"op":"ping"}`)
+	}, "uygulama seviyesi ping gönderilmedi")
+
+	received := server.Received()
+	if received[0] != `{"args":["kline.1.BTCUSDT"],"op":"subscribe"}` {
+		t.Errorf("ilk mesaj abonelik olmalı, gelen: %s", received[0])
+	}
+
+	want := []domain.Candle{
+		klineCandle(1717999920000, 69978.5, 70028.9, 69970, 70000.4),
+		klineCandle(1717999980000, 70000.4, 70019.5, 69988.2, 70011),
+	}
+	if got := sink.WaitClosed(t, 2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mumlar beklenenden farklı:\n got %+v\nwant %+v", got, want)
+	}
+	if n := sink.Partial(); n != 0 {
+		t.Errorf("partial kline kapalıyken %d kapanmamış mum iletildi", n)
+	}
+}
@@ -0,0 +1,215 @@
+package okx
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"v2-trading-bot/internal/adapters/broker/stream"
+	"v2-trading-bot/internal/adapters/metrics"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+	"v2-trading-bot/internal/logger"
+)
+
+// OKXAdapter: OKX public mum (candle) akışını dinler.
+// ports.MarketDataSource'u implemente eder.
+type OKXAdapter struct {
+	service ports.TradingService
+	url     string
+	// pingInterval: Bağlantının açık kalması için uygulama seviyesi ping aralığı.
+	pingInterval time.Duration
+
+	// partialKlines: true ise henüz kapanmamış mumlar da ProcessPartialCandle'a iletilir.
+	partialKlines bool
+
+	// recorder: Opsiyonel. Varsa ham mesajlar işlenmeden önce diske yazılır.
+	recorder *stream.Recorder
+	// tracker: Opsiyonel. Varsa bağlantı sağlığı (mesaj hızı, yeniden bağlanma) raporlanır.
+	tracker *stream.Tracker
+
+	log *slog.Logger
+}
+
+// NewOKXAdapter: Adaptörü oluşturur. Mum kanalları OKX'te "business" uç noktasındadır.
+func NewOKXAdapter(service ports.TradingService) *OKXAdapter {
+	return &OKXAdapter{
+		service:      service,
+		url:          "wss://ws.okx.com:8443/ws/v5/business",
+		pingInterval: 25 * time.Second,
+		log:          logger.For(domain.ExchangeOKX),
+	}
+}
+
+// Name: ports.MarketDataSource
+func (o *OKXAdapter) Name() string {
+	return domain.ExchangeOKX
+}
+
+// SetPartialKlines: Kapanmamış (in-progress) mumların da işlenip işlenmeyeceğini ayarlar.
+func (o *OKXAdapter) SetPartialKlines(enabled bool) {
+	o.partialKlines = enabled
+}
+
+// SetRecorder: Ham mesajların kaydedileceği Recorder'ı ayarlar. Connect'ten önce çağrılmalıdır.
+func (o *OKXAdapter) SetRecorder(recorder *stream.Recorder) {
+	o.recorder = recorder
+}
+
+// SetTracker: Bağlantı sağlığının raporlanacağı Tracker'ı ayarlar. Connect'ten önce çağrılmalıdır.
+func (o *OKXAdapter) SetTracker(tracker *stream.Tracker) {
+	o.tracker = tracker
+}
+
+// RegisterReplay: Kaydedilmiş okx akışlarını bu adaptörün işleyicisine bağlar.
+func (o *OKXAdapter) RegisterReplay(replayer *stream.Replayer) {
+	replayer.Handle("okx.candle.", o.handleMessage)
+}
+
+// Connect: ports.MarketDataSource. OKX sembolleri tireli yazar (BTC-USDT) ve
+// 30 saniye mesaj gelmezse bağlantıyı kapatır, o yüzden 25 saniyede bir "ping" gönderiyoruz.
+func (o *OKXAdapter) Connect(symbol string) {
+	instID, err := toInstID(symbol)
+	if err != nil {
+		o.log.Error("akış başlatılamadı", "symbol", symbol, "err", err)
+		return
+	}
+
+	sub, _ := json.Marshal(map[string]any{
+		"op":   "subscribe",
+		"args": []map[string]string{{"channel": "candle1m", "instId": instID}},
+	})
+
+	name := "okx.candle." + domain.NormalizeSymbol(symbol)
+	stream.Listen(stream.Config{
+		URL:          o.url,
+		Name:         name,
+		Tracker:      o.tracker,
+		Logger:       o.log,
+		Subscribe:    [][]byte{sub},
+		PingInterval: o.pingInterval,
+		PingMessage:  []byte("ping"),
+	}, o.recorder.Wrap(name, o.handleMessage))
+}
+
+func (o *OKXAdapter) handleMessage(message []byte) {
+	received := time.Now() // receive/parse span'ları bu andan başlar
+
+	// Ping cevabı JSON değil, düz "pong" metni.
+	if string(message) == "pong" {
+		return
+	}
+
+	var event OKXCandleEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		o.log.Warn("candle JSON parse hatası", "err", err)
+		metrics.ParseErrors.WithLabelValues("okx", "candle").Inc()
+		return
+	}
+	// Abonelik cevabı / hata mesajlarında data olmaz.
+	if event.Event != "" || !strings.HasPrefix(event.Arg.Channel, "candle") {
+		if event.Event == "error" {
+			o.log.Error("borsa hata mesajı gönderdi", "message", string(message))
+		}
+		return
+	}
+
+	candles, err := event.ToDomain()
+	if err != nil {
+		o.log.Warn("candle çeviri hatası", "err", err)
+		metrics.ParseErrors.WithLabelValues("okx", "candle").Inc()
+		return
+	}
+	for _, candle := range candles {
+		if !candle.IsClosed && !o.partialKlines {
+			continue
+		}
+		// Akış başına sırayla işlenir; ayrı goroutine'ler eski canlı mumu yenisinin üzerine yazabilirdi.
+		o.process(received, candle)
+	}
+}
+
+func (o *OKXAdapter) process(received time.Time, candle domain.Candle) {
+	candle.CorrelationID = domain.NewCorrelationID()
+	log := o.log.With("cid", candle.CorrelationID, "symbol", candle.Symbol, "interval", candle.Interval)
+
+	ctx := stream.StartReceive(domain.ExchangeOKX, received, candle)
+	defer stream.EndReceive(ctx)
+
+	var err error
+	if candle.IsClosed {
+		log.Debug("kapanan mum alındı", "open_time", candle.OpenTime, "close", candle.Close)
+		err = o.service.ProcessIncomingCandle(ctx, candle)
+	} else {
+		err = o.service.ProcessPartialCandle(ctx, candle)
+	}
+	if err != nil {
+		log.Error("mum işlenemedi", "closed", candle.IsClosed, "err", err)
+	}
+}
+
+// DTO - OKX candle mesajı
+// {"arg":{"channel":"candle1m","instId":"BTC-USDT"},"data":[["ts","o","h","l","c","vol","volCcy","volCcyQuote","confirm"]]}
+type OKXCandleEvent struct {
+	Event string `json:"event"`
+	Arg   struct {
+		Channel string `json:"channel"`
+		InstID  string `json:"instId"`
+	} `json:"arg"`
+	Data [][]string `json:"data"`
+}
+
+// ToDomain: OKX mumlarını domain Candle'a çevirir.
+func (e *OKXCandleEvent) ToDomain() ([]domain.Candle, error) {
+	interval := strings.TrimPrefix(e.Arg.Channel, "candle")
+	length, err := domain.IntervalDuration(interval)
+	if err != nil {
+		return nil, err
+	}
+	symbol := domain.NormalizeSymbol(e.Arg.InstID)
+
+	candles := make([]domain.Candle, 0, len(e.Data))
+	for _, row := range e.Data {
+		if len(row) < 9 {
+			return nil, fmt.Errorf("%s: eksik alanlı mum (%d alan)", symbol, len(row))
+		}
+		ts, err := strconv.ParseInt(row[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: geçersiz zaman %q", symbol, row[0])
+		}
+		openTime := time.UnixMilli(ts)
+
+		var p stream.FloatParser
+		candle := domain.Candle{
+			Exchange:    domain.ExchangeOKX,
+			Symbol:      symbol,
+			Interval:    interval,
+			Open:        p.Parse("open", row[1]),
+			High:        p.Parse("high", row[2]),
+			Low:         p.Parse("low", row[3]),
+			Close:       p.Parse("close", row[4]),
+			Volume:      p.Parse("vol", row[5]),
+			QuoteVolume: p.Parse("volCcyQuote", row[7]),
+			OpenTime:    openTime,
+			// Binance ile aynı kural: kapanış zamanı bir sonraki mumun açılışından 1ms önce.
+			CloseTime: openTime.Add(length - time.Millisecond),
+			IsClosed:  row[8] == "1",
+		}
+		if p.Err != nil {
+			return nil, fmt.Errorf("%s mum çevrilemedi: %w", symbol, p.Err)
+		}
+		candles = append(candles, candle)
+	}
+	return candles, nil
+}
+
+// toInstID: "BTCUSDT" -> "BTC-USDT"
+func toInstID(symbol string) (string, error) {
+	base, quote, ok := domain.SplitSymbol(symbol)
+	if !ok {
+		return "", fmt.Errorf("sembol base/quote olarak ayrılamadı: %s", symbol)
+	}
+	return base + "-" + quote, nil
+}
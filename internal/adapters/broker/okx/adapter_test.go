@@ -0,0 +1,65 @@
+package okx
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+	"v2-trading-bot/internal/adapters/broker/stream/streamtest"
+	"v2-trading-bot/internal/core/domain"
+)
+
+func candle(openTime int64, open, high, low, close float64) domain.Candle {
+	return domain.Candle{
+		Exchange:    domain.ExchangeOKX,
+		Symbol:      "BTCUSDT",
+		Interval:    "1m",
+		Open:        open,
+		High:        high,
+		Low:         low,
+		Close:       close,
+		Volume:      4.1822,
+		QuoteVolume: 292700.3,
+		OpenTime:    time.UnixMilli(openTime),
+		CloseTime:   time.UnixMilli(openTime + 59999),
+		IsClosed:    true,
+	}
+}
+
+// Fixture: abonelik cevabı, kapanmamış mum, kapanmış mum, "pong", yarım JSON,
+// geçersiz fiyatlı mum, hata olayı, kapanmış mum.
+func TestConnectReplaysCandleFixture(t *testing.T) {
+	server := streamtest.NewServer(t, "testdata/candle_btcusdt.jsonl")
+	server.Reply = func(message []byte) []byte {
+		if string(message) == "ping" {
+			return []byte("pong")
+		}
+		return nil
+	}
+	sink := &streamtest.Sink{}
+	adapter := NewOKXAdapter(sink)
+	adapter.url = server.URL
+	adapter.pingInterval = 20 * time.Millisecond
+	go adapter.Connect("BTCUSDT")
+
+	sink.WaitClosed(t, 2)
+	streamtest.Eventually(t, 2*time.Second, func() bool {
+		return slices.Contains(server.Received(), "ping")
+	}, "uygulama seviyesi ping gönderilmedi")
+
+	received := server.Received()
+	if received[0] != `{"args":[{"channel":"candle1m","instId":"BTC-USDT"}],"op":"subscribe"}` {
+		t.Errorf("ilk mesaj abonelik olmalı, gelen: %s", received[0])
+	}
+
+	want := []domain.Candle{
+		candle(1717999920000, 69981.2, 70029.4, 69972.8, 70001.7),
+		candle(1717999980000, 70001.7, 70021, 69990.1, 70012.3),
+	}
+	if got := sink.WaitClosed(t, 2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mumlar beklenenden farklı:\n got %+v\nwant %+v", got, want)
+	}
+	if n := sink.Partial(); n != 0 {
+		t.Errorf("partial kline kapalıyken %d kapanmamış mum iletildi", n)
+	}
+}
@@ -0,0 +1,29 @@
+package stream
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// FloatParser: Borsaların string olarak gönderdiği sayıları sırayla parse eder, ilk hatayı saklar.
+// Her ParseFloat çağrısından sonra "if err != nil" yazmamak için:
+//
+//	var p stream.FloatParser
+//	open := p.Parse("open", k.Open)
+//	...
+//	if p.Err != nil { ... }
+type FloatParser struct {
+	Err error
+}
+
+func (p *FloatParser) Parse(field, value string) float64 {
+	if p.Err != nil {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.Err = fmt.Errorf("%s alanı geçersiz (%q): %w", field, value, err)
+		return 0
+	}
+	return f
+}
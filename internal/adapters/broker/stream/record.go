@@ -0,0 +1,189 @@
+package stream
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"v2-trading-bot/internal/logger"
+)
+
+// Record: Kayıt dosyasındaki tek satır. Data, borsadan gelen ham mesajın birebir kopyasıdır
+// (OKX'in "pong"u gibi JSON olmayan mesajlar da olduğu için string tutulur).
+type Record struct {
+	Time   time.Time `json:"t"`      // Mesajın alındığı an
+	Stream string    `json:"stream"` // Örn: binance.kline.BTCUSDT
+	Data   string    `json:"data"`
+}
+
+// Recorder: Ham WebSocket mesajlarını alındıkları zamanla birlikte gzip'li JSONL dosyalarına yazar.
+// Her akış için saatlik ayrı dosya açılır: <dir>/<stream>/<2006010215>.jsonl.gz
+// Dosyalar sadece sona eklenir (append); yeniden başlatmada yeni bir gzip üyesi eklenir,
+// gzip okuyucuları ardışık üyeleri tek dosya gibi okur.
+type Recorder struct {
+	dir string
+	log *slog.Logger
+
+	mu     sync.Mutex
+	files  map[string]*recordFile
+	closed bool
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+type recordFile struct {
+	hour string
+	file *os.File
+	gz   *gzip.Writer
+	buf  *bufio.Writer
+}
+
+// NewRecorder: Kayıtları dir altına yazan bir Recorder oluşturur ve periyodik flush'ı başlatır.
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("kayıt klasörü oluşturulamadı: %w", err)
+	}
+	r := &Recorder{
+		dir:   dir,
+		log:   logger.For("recorder"),
+		files: make(map[string]*recordFile),
+		stop:  make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.flushLoop()
+	return r, nil
+}
+
+// Wrap: handle'ı, mesajı önce kaydedecek şekilde sarar. name akışın adıdır (Örn: binance.kline.BTCUSDT).
+// Kayıt hatası akışı durdurmaz, sadece loglanır. r nil ise handle olduğu gibi döner (kayıt kapalı).
+func (r *Recorder) Wrap(name string, handle func(message []byte)) func(message []byte) {
+	if r == nil {
+		return handle
+	}
+	return func(message []byte) {
+		if err := r.write(name, time.Now(), message); err != nil {
+			r.log.Error("mesaj kaydedilemedi", "stream", name, "err", err)
+		}
+		handle(message)
+	}
+}
+
+func (r *Recorder) write(name string, at time.Time, message []byte) error {
+	line, err := json.Marshal(Record{Time: at, Stream: name, Data: string(message)})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return fmt.Errorf("recorder kapalı")
+	}
+
+	f, err := r.fileLocked(name, at.UTC().Format("2006010215"))
+	if err != nil {
+		return err
+	}
+	if _, err := f.buf.Write(line); err != nil {
+		return err
+	}
+	return f.buf.WriteByte('\n')
+}
+
+// fileLocked: Akışın o saatki dosyasını döner; saat değiştiyse eskisini kapatıp yenisini açar.
+func (r *Recorder) fileLocked(name, hour string) (*recordFile, error) {
+	if f := r.files[name]; f != nil {
+		if f.hour == hour {
+			return f, nil
+		}
+		if err := f.close(); err != nil {
+			r.log.Error("kayıt dosyası kapatılamadı", "stream", name, "err", err)
+		}
+		delete(r.files, name)
+	}
+
+	dir := filepath.Join(r.dir, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(dir, hour+".jsonl.gz"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(file)
+	f := &recordFile{hour: hour, file: file, gz: gz, buf: bufio.NewWriter(gz)}
+	r.files[name] = f
+	return f, nil
+}
+
+// flushLoop: Çökme durumunda en fazla ~1 saniyelik kayıt kaybolsun diye tamponları düzenli boşaltır.
+func (r *Recorder) flushLoop() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			for name, f := range r.files {
+				if err := f.flush(); err != nil {
+					r.log.Error("kayıt diske yazılamadı", "stream", name, "err", err)
+				}
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+// Close: Tüm dosyaları boşaltıp kapatır. Sonraki kayıtlar reddedilir.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	r.mu.Unlock()
+
+	close(r.stop)
+	r.wg.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var firstErr error
+	for name, f := range r.files {
+		if err := f.close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	r.files = nil
+	return firstErr
+}
+
+func (f *recordFile) flush() error {
+	if err := f.buf.Flush(); err != nil {
+		return err
+	}
+	return f.gz.Flush()
+}
+
+func (f *recordFile) close() error {
+	if err := f.buf.Flush(); err != nil {
+		f.file.Close()
+		return err
+	}
+	if err := f.gz.Close(); err != nil {
+		f.file.Close()
+		return err
+	}
+	return f.file.Close()
+}
@@ -0,0 +1,180 @@
+package stream
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"v2-trading-bot/internal/logger"
+)
+
+// Replayer: Recorder'ın yazdığı dosyaları, kayıttaki zaman aralıklarını koruyarak adaptörlere geri besler.
+// Birden fazla dosya (borsa/akış) zaman sırasıyla birleştirilir; böylece üretimdeki bir olay
+// aynı mesaj sırasıyla tekrar oynatılabilir.
+type Replayer struct {
+	// speed: 1 = gerçek hız, 10 = 10 kat hızlı, 0 = beklemeden olabildiğince hızlı.
+	speed    float64
+	handlers map[string]func(message []byte)
+	log      *slog.Logger
+}
+
+func NewReplayer(speed float64) *Replayer {
+	return &Replayer{speed: speed, handlers: make(map[string]func(message []byte)), log: logger.For("replay")}
+}
+
+// Handle: prefix ile başlayan akışların (Örn: "binance.kline") mesajlarını handle'a yönlendirir.
+// Birden fazla prefix eşleşirse en uzunu kazanır.
+func (p *Replayer) Handle(prefix string, handle func(message []byte)) {
+	p.handlers[prefix] = handle
+}
+
+// Play: Glob desenlerine uyan tüm kayıt dosyalarını oynatır. Bittiğinde (veya hata olursa) döner.
+// Örn: Play([]string{"recordings/binance.kline.BTCUSDT/*.jsonl.gz"})
+func (p *Replayer) Play(patterns []string) error {
+	var paths []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("geçersiz desen %q: %w", pattern, err)
+		}
+		paths = append(paths, matches...)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("oynatılacak kayıt bulunamadı: %v", patterns)
+	}
+	sort.Strings(paths)
+
+	readers := make([]*recordReader, 0, len(paths))
+	defer func() {
+		for _, rr := range readers {
+			rr.close()
+		}
+	}()
+	for _, path := range paths {
+		rr, err := openRecordReader(path)
+		if err != nil {
+			return err
+		}
+		readers = append(readers, rr)
+	}
+
+	var (
+		played   int
+		prevTime time.Time
+	)
+	for {
+		// Sıradaki en eski kaydı bul (k-yollu birleştirme; dosya sayısı az olduğu için düz arama yeterli).
+		var next *recordReader
+		for _, rr := range readers {
+			if rr.err != nil {
+				return rr.err
+			}
+			if rr.done {
+				continue
+			}
+			if next == nil || rr.current.Time.Before(next.current.Time) {
+				next = rr
+			}
+		}
+		if next == nil {
+			p.log.Info("replay bitti", "messages", played, "files", len(paths))
+			return nil
+		}
+
+		rec := next.current
+		if p.speed > 0 && !prevTime.IsZero() {
+			if gap := rec.Time.Sub(prevTime); gap > 0 {
+				time.Sleep(time.Duration(float64(gap) / p.speed))
+			}
+		}
+		prevTime = rec.Time
+
+		if handle := p.handlerFor(rec.Stream); handle != nil {
+			handle([]byte(rec.Data))
+			played++
+		}
+		next.advance()
+	}
+}
+
+func (p *Replayer) handlerFor(stream string) func(message []byte) {
+	var (
+		best    func(message []byte)
+		bestLen = -1
+	)
+	for prefix, handle := range p.handlers {
+		if strings.HasPrefix(stream, prefix) && len(prefix) > bestLen {
+			best, bestLen = handle, len(prefix)
+		}
+	}
+	return best
+}
+
+// recordReader: Tek bir kayıt dosyasını satır satır okur.
+type recordReader struct {
+	path    string
+	file    *os.File
+	gz      *gzip.Reader
+	scanner *bufio.Scanner
+
+	current Record
+	done    bool
+	err     error
+}
+
+func openRecordReader(path string) (*recordReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024) // Derinlik snapshot'ları büyük olabilir
+
+	rr := &recordReader{path: path, file: file, gz: gz, scanner: scanner}
+	rr.advance()
+	return rr, nil
+}
+
+func (rr *recordReader) advance() {
+	for rr.scanner.Scan() {
+		line := rr.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			// Kesilen dosyanın yarım kalmış son satırı: scanner onu da satır olarak döner.
+			if !rr.scanner.Scan() && errors.Is(rr.scanner.Err(), io.ErrUnexpectedEOF) {
+				rr.done = true
+				return
+			}
+			rr.err = fmt.Errorf("%s: bozuk kayıt: %w", rr.path, err)
+			return
+		}
+		rr.current = rec
+		return
+	}
+	// Kapanmadan kesilen dosyanın (çökme) sonu eksik olabilir; o ana kadarki kayıtlar yeterli.
+	if err := rr.scanner.Err(); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		rr.err = fmt.Errorf("%s: %w", rr.path, err)
+	}
+	rr.done = true
+}
+
+func (rr *recordReader) close() {
+	rr.gz.Close()
+	rr.file.Close()
+}
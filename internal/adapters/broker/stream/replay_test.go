@@ -0,0 +1,87 @@
+package stream
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRecorder(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2024, 6, 10, 10, 59, 58, 0, time.UTC)
+	writes := []struct {
+		stream string
+		at     time.Duration
+		data   string
+	}{
+		{"binance.kline.BTCUSDT", 0, "k1"},
+		{"binance.aggTrade.BTCUSDT", 500 * time.Millisecond, "a1"},
+		{"okx.candle.BTCUSDT", time.Second, "o1"}, // İşleyicisi yok, atlanır
+		{"binance.kline.BTCUSDT", 1500 * time.Millisecond, "k2"},
+		// Saat değişti: her akış yeni bir dosyaya geçer.
+		{"binance.aggTrade.BTCUSDT", 2 * time.Second, "a2"},
+		{"binance.kline.BTCUSDT", 3 * time.Second, "k3"},
+		{"binance.aggTrade.BTCUSDT", 4 * time.Second, "a3"},
+	}
+	for _, w := range writes {
+		if err := r.write(w.stream, base.Add(w.at), []byte(w.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*", "*.jsonl.gz"))
+	if len(files) != 5 {
+		t.Fatalf("3 akış ve saat değişimi için 5 dosya bekleniyordu: %v", files)
+	}
+
+	// Çöken süreç: kapanmamış (trailer'ı olmayan) gzip üyesi ve yarım kalmış son satır.
+	truncated := filepath.Join(dir, "binance.aggTrade.BTCUSDT", "2024061011.jsonl.gz")
+	appendCrashedMember(t, truncated, Record{Time: base.Add(5 * time.Second), Stream: "binance.aggTrade.BTCUSDT", Data: "a4"})
+
+	var got []string
+	p := NewReplayer(0)
+	p.Handle("binance.", func(message []byte) { got = append(got, "binance:"+string(message)) })
+	p.Handle("binance.aggTrade.", func(message []byte) { got = append(got, "aggTrade:"+string(message)) })
+	if err := p.Play([]string{filepath.Join(dir, "*", "*.jsonl.gz")}); err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+
+	// Tüm dosyalar zamana göre birleşir; en uzun prefix kazanır.
+	want := []string{
+		"binance:k1", "aggTrade:a1", "binance:k2", "aggTrade:a2", "binance:k3", "aggTrade:a3", "aggTrade:a4",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("oynatma sırası\n got: %v\nwant: %v", got, want)
+	}
+}
+
+// appendCrashedMember: Dosyaya flush edilmiş ama kapatılmamış bir gzip üyesi ekler; rec tam satır,
+// ardından yarım bir satır yazılır.
+func appendCrashedMember(t *testing.T, path string, rec Record) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	line, _ := json.Marshal(rec)
+	gz := gzip.NewWriter(file)
+	gz.Write(append(line, '\n'))
+	gz.Write(line[:len(line)/2])
+	if err := gz.Flush(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,120 @@
+package stream
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+	"v2-trading-bot/internal/adapters/metrics"
+	"v2-trading-bot/internal/logger"
+
+	"github.com/gorilla/websocket"
+)
+
+// Config: Borsa WebSocket bağlantısının ayarları.
+// Binance aboneliği URL'de yapar; Bybit/OKX ise bağlandıktan sonra mesajla abone olmayı ve
+// bağlantının kapanmaması için düzenli ping atmayı ister.
+type Config struct {
+	URL string
+
+	// Name: Akışın adı (Örn: binance.kline.BTCUSDT). Boşsa URL kullanılır.
+	Name string
+	// Tracker: Opsiyonel. Varsa bağlantı durumu ve mesaj hızı buraya raporlanır.
+	Tracker *Tracker
+	// Logger: Bağlantı olaylarının yazılacağı logger. Boşsa "stream" bileşeni kullanılır.
+	Logger *slog.Logger
+
+	// Subscribe: Her (yeniden) bağlantıdan sonra sırayla gönderilecek mesajlar.
+	Subscribe [][]byte
+
+	// PingInterval > 0 ise bu aralıkla PingMessage metin mesajı olarak gönderilir.
+	PingInterval time.Duration
+	PingMessage  []byte
+
+	// ReconnectDelay: Bağlantı koptuğunda yeniden bağlanmadan önce beklenen süre (varsayılan 2s).
+	ReconnectDelay time.Duration
+}
+
+// Listen: Bağlanır, abone olur ve gelen her mesajı handle'a verir.
+// Bağlantı koparsa ReconnectDelay kadar bekleyip yeniden bağlanır; asla geri dönmez.
+// handle aynı goroutine'de, mesaj sırasıyla çağrılır.
+func Listen(cfg Config, handle func(message []byte)) {
+	delay := cfg.ReconnectDelay
+	if delay <= 0 {
+		delay = 2 * time.Second
+	}
+
+	if cfg.Name == "" {
+		cfg.Name = cfg.URL
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = logger.For("stream")
+	}
+	cfg.Logger = cfg.Logger.With("stream", cfg.Name)
+
+	for {
+		err := session(cfg, handle)
+		if err != nil {
+			cfg.Logger.Warn("WebSocket oturumu bitti", "err", err)
+			metrics.StreamSessionsEnded.WithLabelValues(cfg.Name).Inc()
+		}
+		cfg.Tracker.disconnected(cfg.Name, cfg.URL, err)
+		time.Sleep(delay)
+	}
+}
+
+// session: Tek bir bağlantının ömrü. Bağlantı koptuğunda hatayla döner.
+func session(cfg Config, handle func(message []byte)) error {
+	cfg.Logger.Info("bağlanılıyor", "url", cfg.URL)
+
+	conn, _, err := websocket.DefaultDialer.Dial(cfg.URL, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// Watchdog bağlantıyı kapatırsa ReadMessage hata döner ve Listen yeniden bağlanır.
+	cfg.Tracker.connected(cfg.Name, cfg.URL, func() { conn.Close() })
+
+	for _, msg := range cfg.Subscribe {
+		if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+			return err
+		}
+	}
+
+	// gorilla/websocket aynı anda tek yazara izin verir; abonelikten sonra sadece ping goroutine'i yazar.
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		wg.Wait()
+	}()
+	if cfg.PingInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(cfg.PingInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					if err := conn.WriteMessage(websocket.TextMessage, cfg.PingMessage); err != nil {
+						// Okuma tarafı da hata alıp oturumu kapatacak.
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	received := metrics.MessagesReceived.WithLabelValues(cfg.Name)
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		cfg.Tracker.message(cfg.Name)
+		received.Inc()
+		handle(message)
+	}
+}
@@ -0,0 +1,156 @@
+// Package streamtest: Borsa adaptörlerini kaydedilmiş mesajlarla test etmek için yerel WebSocket sunucusu.
+package streamtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"v2-trading-bot/internal/adapters/broker/stream"
+
+	"github.com/gorilla/websocket"
+)
+
+// Server: Bağlanan her istemciye fixture dosyasındaki mesajları (stream.Record JSONL, Recorder'ın
+// yazdığı satırların sıkıştırılmamış hali) sırayla gönderir, ardından bir ping kontrol frame'i atar.
+// İstemcinin gönderdiği metin mesajları (abonelik, uygulama seviyesi ping) Received'da tutulur.
+type Server struct {
+	URL string // ws://127.0.0.1:port
+
+	// Reply: Opsiyonel. İstemcinin metin mesajına verilecek cevap (Örn: "ping" -> "pong"); nil ise cevap yok.
+	Reply func(message []byte) []byte
+
+	server   *httptest.Server
+	messages [][]byte
+
+	mu       sync.Mutex
+	received []string
+	pongs    int
+}
+
+// NewServer: Fixture'ı okur ve sunucuyu başlatır. Test bitince sunucu kapanır.
+func NewServer(t testing.TB, fixture string) *Server {
+	t.Helper()
+	s := &Server{messages: readFixture(t, fixture)}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	s.URL = "ws" + strings.TrimPrefix(s.server.URL, "http")
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func readFixture(t testing.TB, path string) [][]byte {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("fixture açılamadı: %v", err)
+	}
+	defer file.Close()
+
+	var messages [][]byte
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var rec stream.Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("fixture satırı okunamadı (%s): %v", path, err)
+		}
+		messages = append(messages, []byte(rec.Data))
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("fixture okunamadı: %v", err)
+	}
+	return messages
+}
+
+var upgrader = websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// gorilla/websocket tek yazara izin verir; okuma goroutine'i de cevap yazdığı için kilitli yazılır.
+	var writeMu sync.Mutex
+	write := func(message []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteMessage(websocket.TextMessage, message)
+	}
+
+	conn.SetPongHandler(func(string) error {
+		s.mu.Lock()
+		s.pongs++
+		s.mu.Unlock()
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.received = append(s.received, string(message))
+			s.mu.Unlock()
+			if s.Reply == nil {
+				continue
+			}
+			if reply := s.Reply(message); reply != nil {
+				if err := write(reply); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for _, message := range s.messages {
+		if err := write(message); err != nil {
+			return
+		}
+	}
+	writeMu.Lock()
+	err = conn.WriteControl(websocket.PingMessage, []byte("fixture"), time.Now().Add(time.Second))
+	writeMu.Unlock()
+	if err != nil {
+		return
+	}
+	<-done
+}
+
+// Received: İstemcinin şimdiye kadar gönderdiği metin mesajları.
+func (s *Server) Received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.received...)
+}
+
+// Pongs: İstemcinin ping kontrol frame'lerine verdiği pong sayısı.
+func (s *Server) Pongs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pongs
+}
+
+// Eventually: cond doğru olana kadar (en fazla timeout) bekler.
+func Eventually(t testing.TB, timeout time.Duration, cond func() bool, msg string) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("zaman aşımı: %s", msg)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
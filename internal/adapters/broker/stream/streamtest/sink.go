@@ -0,0 +1,60 @@
+package streamtest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// Sink: Adaptörün ilettiği mumları toplayan ports.TradingService.
+type Sink struct {
+	mu      sync.Mutex
+	closed  []domain.Candle
+	partial []domain.Candle
+}
+
+// ProcessIncomingCandle: ports.TradingService
+func (s *Sink) ProcessIncomingCandle(_ context.Context, candle domain.Candle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = append(s.closed, candle)
+	return nil
+}
+
+// ProcessPartialCandle: ports.TradingService
+func (s *Sink) ProcessPartialCandle(_ context.Context, candle domain.Candle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.partial = append(s.partial, candle)
+	return nil
+}
+
+// WaitClosed: n kapanmış mum gelene kadar bekler. Adaptörler mumları ayrı goroutine'lerde işlediği
+// için sonuç açılış zamanına göre sıralanır; CorrelationID karşılaştırmada işe yaramadığından silinir.
+func (s *Sink) WaitClosed(t testing.TB, n int) []domain.Candle {
+	t.Helper()
+	Eventually(t, 5*time.Second, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.closed) >= n
+	}, "kapanmış mumlar gelmedi")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := append([]domain.Candle(nil), s.closed...)
+	for i := range out {
+		out[i].CorrelationID = ""
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].OpenTime.Before(out[j].OpenTime) })
+	return out
+}
+
+// Partial: Şimdiye kadar gelen kapanmamış mum sayısı.
+func (s *Sink) Partial() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.partial)
+}
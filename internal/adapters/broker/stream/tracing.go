@@ -0,0 +1,37 @@
+package stream
+
+import (
+	"context"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("v2-trading-bot/adapters/broker")
+
+// StartReceive: Mesajın alındığı andan başlayan "<borsa> receive" span'ını ve onun altında
+// (JSON çözme + domain'e çevirme süresini gösteren) "parse" span'ını açar.
+// Dönen context servise verilir; receive span'ı mum işlendikten sonra EndReceive ile kapatılmalıdır.
+func StartReceive(exchange string, received time.Time, candle domain.Candle) context.Context {
+	ctx, _ := tracer.Start(context.Background(), exchange+" receive",
+		trace.WithTimestamp(received),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("exchange", exchange),
+			attribute.String("symbol", candle.Symbol),
+			attribute.String("interval", candle.Interval),
+			attribute.Bool("closed", candle.IsClosed),
+			attribute.String("cid", candle.CorrelationID),
+		))
+	_, parse := tracer.Start(ctx, "parse", trace.WithTimestamp(received))
+	parse.End()
+	return ctx
+}
+
+// EndReceive: StartReceive ile açılan span'ı kapatır.
+func EndReceive(ctx context.Context) {
+	trace.SpanFromContext(ctx).End()
+}
@@ -0,0 +1,202 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/logger"
+)
+
+// rateWindow: Mesaj hızının hesaplandığı pencere.
+const rateWindow = time.Minute
+
+// Tracker: Listen ile açılan bağlantıların sağlığını (bağlantı durumu, mesaj hızı, yeniden bağlanma
+// sayısı) tutar ve gerektiğinde bağlantıyı zorla kapatıp yeniden kurdurur.
+// ports.StreamHealthProvider ve ports.FeedReconnector'ı implemente eder.
+// nil *Tracker ile çağrılan metotlar hiçbir şey yapmaz (izleme kapalı).
+type Tracker struct {
+	mu      sync.Mutex
+	streams map[string]*streamStats
+	log     *slog.Logger
+}
+
+type streamStats struct {
+	name string
+	url  string
+
+	connected   bool
+	connectedAt time.Time
+	lastMessage time.Time
+	messages    int64
+	sessions    int // Toplam oturum sayısı; ilki hariç hepsi yeniden bağlanmadır
+	lastError   string
+
+	windowStart time.Time
+	windowCount int64
+	rate        float64 // Tamamlanmış son pencerenin dakikalık hızı
+
+	// closeConn: Açık bağlantıyı kapatır (zorla yeniden bağlanma için). Bağlı değilken nil.
+	closeConn func()
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{streams: make(map[string]*streamStats), log: logger.For("stream")}
+}
+
+func (t *Tracker) stats(name, url string) *streamStats {
+	st := t.streams[name]
+	if st == nil {
+		st = &streamStats{name: name, url: url}
+		t.streams[name] = st
+	}
+	return st
+}
+
+// connected: Yeni oturum açıldı.
+func (t *Tracker) connected(name, url string, closeConn func()) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st := t.stats(name, url)
+	st.connected = true
+	st.connectedAt = time.Now()
+	st.sessions++
+	st.closeConn = closeConn
+}
+
+// disconnected: Oturum (veya bağlanma denemesi) hatayla bitti.
+func (t *Tracker) disconnected(name, url string, err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st := t.stats(name, url)
+	st.connected = false
+	st.closeConn = nil
+	if err != nil {
+		st.lastError = err.Error()
+	}
+}
+
+func (t *Tracker) message(name string) {
+	if t == nil {
+		return
+	}
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st := t.streams[name]
+	if st == nil {
+		return
+	}
+	st.lastMessage = now
+	st.messages++
+	if st.windowStart.IsZero() {
+		st.windowStart = now
+	}
+	if elapsed := now.Sub(st.windowStart); elapsed >= rateWindow {
+		st.rate = float64(st.windowCount) / elapsed.Minutes()
+		st.windowStart, st.windowCount = now, 0
+	}
+	st.windowCount++
+}
+
+// ReconnectFeed: ports.FeedReconnector. İsmi "<exchange>.<tür>.<SYMBOL>" formatında olan
+// tüm bağlantıları kapatır; Listen döngüsü kısa süre sonra yeniden bağlanır.
+func (t *Tracker) ReconnectFeed(exchange, symbol string) int {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := 0
+	for name, st := range t.streams {
+		if !strings.HasPrefix(name, exchange+".") || !strings.HasSuffix(name, "."+symbol) || st.closeConn == nil {
+			continue
+		}
+		t.log.Warn("akış zorla yeniden bağlanıyor", "stream", name)
+		st.closeConn()
+		st.closeConn = nil
+		n++
+	}
+	return n
+}
+
+// StreamHealth: ports.StreamHealthProvider
+func (t *Tracker) StreamHealth() []domain.StreamHealth {
+	if t == nil {
+		return nil
+	}
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]domain.StreamHealth, 0, len(t.streams))
+	for _, st := range t.streams {
+		h := domain.StreamHealth{
+			Name:          st.name,
+			URL:           st.url,
+			Connected:     st.connected,
+			ConnectedAt:   st.connectedAt,
+			LastMessageAt: st.lastMessage,
+			Messages:      st.messages,
+			Reconnects:    max(st.sessions-1, 0),
+			LastError:     st.lastError,
+		}
+		if !st.lastMessage.IsZero() {
+			h.LagSeconds = now.Sub(st.lastMessage).Seconds()
+		}
+		// İlk pencere henüz dolmadıysa o ana kadarki hız gösterilir.
+		h.MessagesPerMin = st.rate
+		if st.rate == 0 && !st.windowStart.IsZero() {
+			if elapsed := now.Sub(st.windowStart); elapsed > time.Second {
+				h.MessagesPerMin = float64(st.windowCount) / elapsed.Minutes()
+			}
+		}
+		out = append(out, h)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Probe: Bir borsanın akışlarını sağlık kontrolüne çevirir. ports.HealthCheck'i implemente eder.
+// Örn: Probe{Tracker: t, Prefix: "binance.", MaxLag: 2 * time.Minute}
+type Probe struct {
+	Tracker *Tracker
+	Prefix  string
+	// MaxLag: Bağlı bir akışta son mesajdan bu yana izin verilen en uzun sessizlik.
+	MaxLag time.Duration
+}
+
+// CheckHealth: ports.HealthCheck. Prefix'e uyan en az bir akış olmalı ve hepsi bağlı ve taze olmalı.
+func (p Probe) CheckHealth(ctx context.Context) error {
+	var found int
+	for _, h := range p.Tracker.StreamHealth() {
+		if !strings.HasPrefix(h.Name, p.Prefix) {
+			continue
+		}
+		found++
+		if !h.Connected {
+			return fmt.Errorf("%s bağlı değil: %s", h.Name, h.LastError)
+		}
+		if p.MaxLag > 0 && h.LagSeconds > p.MaxLag.Seconds() {
+			return fmt.Errorf("%s %.0f saniyedir mesaj almadı", h.Name, h.LagSeconds)
+		}
+	}
+	if found == 0 {
+		return fmt.Errorf("%s* akışı yok", p.Prefix)
+	}
+	return nil
+}
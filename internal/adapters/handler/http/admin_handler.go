@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminHandler: Sistem yönetimi için REST uç noktaları (disk kullanımı vb.).
+type AdminHandler struct {
+	storage ports.StorageAdminRepository
+}
+
+func NewAdminHandler(storage ports.StorageAdminRepository) *AdminHandler {
+	return &AdminHandler{storage: storage}
+}
+
+// RegisterRoutes: /admin altındaki rotaları verilen router'a bağlar. Sadece admin rolü erişebilir.
+func (h *AdminHandler) RegisterRoutes(router fiber.Router) {
+	admin := router.Group("/admin", RequireRole(domain.RoleAdmin))
+	admin.Get("/storage", h.GetStorageStats)
+}
+
+// GetStorageStats: GET /admin/storage
+// candles hypertable'ının chunk boyutlarını ve sıkıştırma oranlarını döner.
+func (h *AdminHandler) GetStorageStats(c *fiber.Ctx) error {
+	stats, err := h.storage.GetCandleStorageStats()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(stats)
+}
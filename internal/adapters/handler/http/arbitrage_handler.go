@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ArbitrageHandler: Kaydedilmiş arbitraj fırsatları için REST uç noktaları.
+type ArbitrageHandler struct {
+	repo ports.ArbitrageRepository
+}
+
+func NewArbitrageHandler(repo ports.ArbitrageRepository) *ArbitrageHandler {
+	return &ArbitrageHandler{repo: repo}
+}
+
+// RegisterRoutes: /arbitrage rotalarını verilen router'a bağlar.
+func (h *ArbitrageHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/arbitrage", h.GetOpportunities)
+}
+
+// GetOpportunities: GET /arbitrage?symbol=BTCUSDT&limit=50
+// symbol verilmezse tüm semboller döner. En yeni fırsat en üstte.
+func (h *ArbitrageHandler) GetOpportunities(c *fiber.Ctx) error {
+	symbol := ""
+	if raw := c.Query("symbol"); raw != "" {
+		symbol = domain.NormalizeSymbol(raw)
+	}
+	limit := c.QueryInt("limit", 50)
+	if limit <= 0 || limit > 1000 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "limit 1 ile 1000 arasında olmalı"})
+	}
+
+	opportunities, err := h.repo.GetOpportunities(symbol, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if opportunities == nil {
+		opportunities = []domain.ArbitrageOpportunity{}
+	}
+	return c.JSON(opportunities)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+	"v2-trading-bot/internal/adapters/auth"
+	"v2-trading-bot/internal/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuthHandler: Giriş, token yenileme ve kullanıcı yönetimi için REST uç noktaları.
+type AuthHandler struct {
+	authn *auth.Authenticator
+}
+
+func NewAuthHandler(authn *auth.Authenticator) *AuthHandler {
+	return &AuthHandler{authn: authn}
+}
+
+// RegisterPublicRoutes: Kimlik doğrulama gerektirmeyen rotalar (/auth/login).
+// Authenticate middleware'inden ÖNCE bağlanmalıdır.
+func (h *AuthHandler) RegisterPublicRoutes(router fiber.Router) {
+	router.Post("/auth/login", h.Login)
+}
+
+// RegisterRoutes: Giriş yapmış kullanıcıların rotaları. /users sadece admin içindir.
+func (h *AuthHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/auth/me", h.Me)
+	router.Post("/auth/token", h.Token)
+
+	users := router.Group("/users", RequireRole(domain.RoleAdmin))
+	users.Get("/", h.ListUsers)
+	users.Post("/", h.CreateUser)
+	users.Post("/:username/api-key", h.CreateAPIKey)
+}
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Login: POST /auth/login {"username": "...", "password": "..."}
+// Dönen token REST'te "Authorization: Bearer" olarak, Centrifuge'de connect token olarak kullanılır.
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req loginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "geçersiz istek gövdesi"})
+	}
+	session, err := h.authn.Login(req.Username, req.Password)
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(session)
+}
+
+// Token: POST /auth/token
+// Geçerli token veya API anahtarıyla yeni bir token üretir (süre uzatma, Centrifuge için token alma).
+func (h *AuthHandler) Token(c *fiber.Ctx) error {
+	session, err := h.authn.Refresh(CurrentUser(c).Subject)
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(session)
+}
+
+// Me: GET /auth/me
+func (h *AuthHandler) Me(c *fiber.Ctx) error {
+	claims := CurrentUser(c)
+	accounts := claims.Accounts
+	if accounts == nil {
+		accounts = []string{}
+	}
+	return c.JSON(fiber.Map{
+		"username": claims.Subject,
+		"role":     claims.Role,
+		"accounts": accounts,
+	})
+}
+
+// ListUsers: GET /users (admin)
+func (h *AuthHandler) ListUsers(c *fiber.Ctx) error {
+	users, err := h.authn.ListUsers()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if users == nil {
+		users = []domain.User{}
+	}
+	return c.JSON(users)
+}
+
+type createUserRequest struct {
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	Role     string   `json:"role"`
+	Accounts []string `json:"accounts"`
+}
+
+// CreateUser: POST /users (admin)
+// {"username": "ali", "password": "...", "role": "trader", "accounts": ["demo"]}
+func (h *AuthHandler) CreateUser(c *fiber.Ctx) error {
+	var req createUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "geçersiz istek gövdesi"})
+	}
+	role, err := domain.ParseRole(req.Role)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	user, err := h.authn.CreateUser(req.Username, req.Password, role, req.Accounts)
+	if errors.Is(err, domain.ErrUserExists) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(user)
+}
+
+// CreateAPIKey: POST /users/:username/api-key (admin)
+// Yeni anahtar üretir ve sadece bu yanıtta gösterir; varsa eskisi geçersiz olur.
+func (h *AuthHandler) CreateAPIKey(c *fiber.Ctx) error {
+	key, err := h.authn.NewAPIKey(c.Params("username"))
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"api_key": key})
+}
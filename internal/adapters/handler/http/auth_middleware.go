@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"v2-trading-bot/internal/adapters/auth"
+	"v2-trading-bot/internal/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// claimsLocal: Doğrulanmış kullanıcının fiber.Ctx.Locals anahtarı.
+const claimsLocal = "auth.claims"
+
+// Authenticate: İsteği "Authorization: Bearer <jwt>" veya "X-API-Key: <anahtar>" ile doğrular.
+// Başarısızsa 401 döner; başarılıysa kullanıcı CurrentUser ile, servislerde ise c.UserContext()
+// üzerinden auth.FromContext ile okunabilir.
+func Authenticate(authn *auth.Authenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		apiKey := c.Get("X-API-Key")
+		claims, err := verifyHeaders(authn, apiKey, c.Get(fiber.HeaderAuthorization))
+		if err == nil && claims == nil {
+			err = errors.New("kimlik doğrulama gerekli (Authorization: Bearer <token> veya X-API-Key)")
+		}
+		if err != nil {
+			if apiKey != "" && !errors.Is(err, auth.ErrInvalidCredentials) {
+				// Anahtar aranırken veritabanı hatası: istemcinin suçu değil.
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+		c.Locals(claimsLocal, claims)
+		c.SetUserContext(auth.NewContext(c.UserContext(), claims))
+		return c.Next()
+	}
+}
+
+// verifyHeaders: X-API-Key veya "Authorization: Bearer" başlığını doğrular.
+// İkisi de yoksa (nil, nil) döner.
+func verifyHeaders(authn *auth.Authenticator, apiKey, authorization string) (*auth.Claims, error) {
+	if apiKey != "" {
+		return authn.VerifyAPIKey(apiKey)
+	}
+	if token, ok := strings.CutPrefix(authorization, "Bearer "); ok {
+		return authn.VerifyToken(strings.TrimSpace(token))
+	}
+	return nil, nil
+}
+
+// RequireRole: Kullanıcının rolü en az role ise devam eder, değilse 403 döner.
+// Authenticate'ten sonra kullanılmalıdır.
+func RequireRole(role domain.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := CurrentUser(c)
+		if claims == nil || !claims.Role.Allows(role) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "bu işlem için " + string(role) + " rolü gerekli"})
+		}
+		return c.Next()
+	}
+}
+
+// CurrentUser: Authenticate'in doğruladığı kullanıcı (yoksa nil).
+func CurrentUser(c *fiber.Ctx) *auth.Claims {
+	claims, _ := c.Locals(claimsLocal).(*auth.Claims)
+	return claims
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"strings"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CandleHandler: Geçmiş mum verisi için REST uç noktaları.
+type CandleHandler struct {
+	repo ports.CandleRepository
+}
+
+func NewCandleHandler(repo ports.CandleRepository) *CandleHandler {
+	return &CandleHandler{repo: repo}
+}
+
+// RegisterRoutes: /candles rotalarını verilen router'a bağlar.
+func (h *CandleHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/candles", h.GetCandles)
+}
+
+// GetCandles: GET /candles?exchange=binance&symbol=BTCUSDT&interval=1m&limit=100
+// Mumları eskiden yeniye (grafik çizimi için) sıralı döner.
+func (h *CandleHandler) GetCandles(c *fiber.Ctx) error {
+	exchange := strings.ToLower(c.Query("exchange", domain.ExchangeBinance))
+	symbol := domain.NormalizeSymbol(c.Query("symbol", "BTCUSDT"))
+	interval := c.Query("interval", "1m")
+	limit := c.QueryInt("limit", 100)
+	if limit <= 0 || limit > 1000 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "limit 1 ile 1000 arasında olmalı"})
+	}
+
+	candles, err := h.repo.GetLatestCandles(exchange, symbol, interval, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// Repository DESC döner, grafik için ASC'ye çeviriyoruz.
+	for i, j := 0, len(candles)-1; i < j; i, j = i+1, j-1 {
+		candles[i], candles[j] = candles[j], candles[i]
+	}
+	if candles == nil {
+		candles = []domain.Candle{}
+	}
+	return c.JSON(candles)
+}
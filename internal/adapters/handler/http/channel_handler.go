@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ChannelHandler: Abone olunabilecek WebSocket kanallarının listesi (joker abonelik yerine keşif).
+type ChannelHandler struct {
+	channels ports.ChannelDirectory
+}
+
+func NewChannelHandler(channels ports.ChannelDirectory) *ChannelHandler {
+	return &ChannelHandler{channels: channels}
+}
+
+// RegisterRoutes: /channels rotasını verilen router'a bağlar.
+func (h *ChannelHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/channels", h.GetChannels)
+}
+
+// GetChannels: GET /channels?namespace=kline
+// Kullanıcının abone olabileceği kanallar; cüzdan kanalları sadece kendi hesapları için listelenir.
+func (h *ChannelHandler) GetChannels(c *fiber.Ctx) error {
+	namespace := c.Query("namespace")
+	out := []domain.ChannelInfo{}
+	for _, ch := range h.channels.Channels(CurrentUser(c).Accounts) {
+		if namespace == "" || ch.Namespace == namespace {
+			out = append(out, ch)
+		}
+	}
+	return c.JSON(out)
+}
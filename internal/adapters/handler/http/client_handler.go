@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// surveyTimeout: Tüm Centrifuge node'larının yanıt vermesi için beklenen en uzun süre.
+const surveyTimeout = 5 * time.Second
+
+// ClientHandler: Bağlı WebSocket/SSE istemcilerini listeleme ve koparma (sadece admin).
+type ClientHandler struct {
+	clients ports.ClientAdmin
+}
+
+func NewClientHandler(clients ports.ClientAdmin) *ClientHandler {
+	return &ClientHandler{clients: clients}
+}
+
+// RegisterRoutes: /admin/clients rotalarını verilen router'a bağlar.
+func (h *ClientHandler) RegisterRoutes(router fiber.Router) {
+	clients := router.Group("/admin/clients", RequireRole(domain.RoleAdmin))
+	clients.Get("/", h.List)
+	clients.Delete("/:id", h.Disconnect)
+}
+
+// List: GET /admin/clients?user=alice&channel=signals:rsi
+// Tüm api kopyalarındaki istemciler; kullanıcı ve kanalla süzülebilir.
+func (h *ClientHandler) List(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), surveyTimeout)
+	defer cancel()
+
+	clients, err := h.clients.Clients(ctx)
+	if err != nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+	}
+	user, channel := c.Query("user"), c.Query("channel")
+	out := []domain.ConnectedClient{}
+	for _, client := range clients {
+		if (user == "" || client.User == user) && (channel == "" || slices.Contains(client.Channels, channel)) {
+			out = append(out, client)
+		}
+	}
+	return c.JSON(out)
+}
+
+// Disconnect: DELETE /admin/clients/<client_id>
+// İstemci koparılır ve otomatik yeniden bağlanmaz (token'ı hâlâ geçerliyse sayfa yenilenince bağlanabilir).
+func (h *ClientHandler) Disconnect(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), surveyTimeout)
+	defer cancel()
+
+	err := h.clients.DisconnectClient(ctx, c.Params("id"))
+	if errors.Is(err, domain.ErrClientNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HealthHandler: Piyasa verisi akışlarının sağlık durumu için REST uç noktaları.
+type HealthHandler struct {
+	streams ports.StreamHealthProvider
+	feeds   ports.FeedHealthProvider // nil olabilir (watchdog kapalı)
+}
+
+func NewHealthHandler(streams ports.StreamHealthProvider, feeds ports.FeedHealthProvider) *HealthHandler {
+	return &HealthHandler{streams: streams, feeds: feeds}
+}
+
+// RegisterRoutes: /health altındaki rotaları verilen router'a bağlar.
+func (h *HealthHandler) RegisterRoutes(router fiber.Router) {
+	health := router.Group("/health")
+	health.Get("/feeds", h.GetFeeds)
+}
+
+// GetFeeds: GET /health/feeds
+// streams: WebSocket bağlantıları (bağlı mı, son mesajdan beri geçen süre, mesaj hızı, yeniden bağlanma sayısı).
+// feeds: Mum serileri (son mumdan beri geçen süre, bayat mı). Bayat seri varsa 503 döner.
+func (h *HealthHandler) GetFeeds(c *fiber.Ctx) error {
+	streams := []domain.StreamHealth{}
+	if h.streams != nil {
+		if s := h.streams.StreamHealth(); s != nil {
+			streams = s
+		}
+	}
+	feeds := []domain.FeedHealth{}
+	if h.feeds != nil {
+		feeds = h.feeds.FeedHealth()
+	}
+
+	status := fiber.StatusOK
+	healthy := true
+	for _, f := range feeds {
+		if f.Stale {
+			healthy = false
+			status = fiber.StatusServiceUnavailable
+			break
+		}
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"healthy": healthy,
+		"streams": streams,
+		"feeds":   feeds,
+	})
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"time"
+	"v2-trading-bot/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// checkTimeout: Tek bir bağımlılık kontrolü için en uzun süre.
+const checkTimeout = 2 * time.Second
+
+// ProbeHandler: Docker / load balancer için /healthz (liveness) ve /readyz (readiness).
+type ProbeHandler struct {
+	startedAt time.Time
+	checks    []namedCheck
+}
+
+type namedCheck struct {
+	name  string
+	check ports.HealthCheck
+}
+
+// CheckResult: Tek bir bağımlılığın durumu.
+type CheckResult struct {
+	Status    string  `json:"status"` // "ok" veya "fail"
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+}
+
+func NewProbeHandler() *ProbeHandler {
+	return &ProbeHandler{startedAt: time.Now()}
+}
+
+// AddCheck: Bir bağımlılık kontrolü ekler (Örn: "database", "centrifuge", "binance_stream").
+func (h *ProbeHandler) AddCheck(name string, check ports.HealthCheck) {
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+}
+
+// RegisterRoutes: Rotaları uygulamanın köküne bağlar (/api/v1 altına değil).
+func (h *ProbeHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/healthz", h.Healthz)
+	router.Get("/readyz", h.Readyz)
+}
+
+// Healthz: GET /healthz
+// Süreç ayakta ve istek işleyebiliyor mu? Bağımlılık hatalarında bile 200 döner (status: "degraded"),
+// yoksa veritabanı kısa süre gittiğinde Docker konteyneri boşuna yeniden başlatır.
+func (h *ProbeHandler) Healthz(c *fiber.Ctx) error {
+	results, ok := h.run(c.UserContext())
+	status := "ok"
+	if !ok {
+		status = "degraded"
+	}
+	return c.JSON(fiber.Map{
+		"status":         status,
+		"uptime_seconds": time.Since(h.startedAt).Seconds(),
+		"checks":         results,
+	})
+}
+
+// Readyz: GET /readyz
+// Trafik/iş almaya hazır mı? Herhangi bir bağımlılık sağlıksızsa 503 döner.
+func (h *ProbeHandler) Readyz(c *fiber.Ctx) error {
+	results, ok := h.run(c.UserContext())
+	if !ok {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"status": "not_ready", "checks": results})
+	}
+	return c.JSON(fiber.Map{"status": "ready", "checks": results})
+}
+
+// run: Tüm kontrolleri paralel çalıştırır.
+func (h *ProbeHandler) run(parent context.Context) (map[string]CheckResult, bool) {
+	type result struct {
+		name string
+		res  CheckResult
+	}
+	ch := make(chan result, len(h.checks))
+	for _, nc := range h.checks {
+		go func(nc namedCheck) {
+			ctx, cancel := context.WithTimeout(parent, checkTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := nc.check.CheckHealth(ctx)
+			res := CheckResult{Status: "ok", LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+			if err != nil {
+				res.Status, res.Error = "fail", err.Error()
+			}
+			ch <- result{name: nc.name, res: res}
+		}(nc)
+	}
+
+	results := make(map[string]CheckResult, len(h.checks))
+	ok := true
+	for range h.checks {
+		r := <-ch
+		results[r.name] = r.res
+		if r.res.Status != "ok" {
+			ok = false
+		}
+	}
+	return results, ok
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"strings"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// QuarantineHandler: Doğrulamadan geçemeyen (karantinadaki) mumlar için REST uç noktaları.
+type QuarantineHandler struct {
+	repo ports.QuarantineRepository
+}
+
+func NewQuarantineHandler(repo ports.QuarantineRepository) *QuarantineHandler {
+	return &QuarantineHandler{repo: repo}
+}
+
+// RegisterRoutes: /quarantine rotalarını verilen router'a bağlar.
+func (h *QuarantineHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/quarantine", h.GetQuarantined)
+}
+
+// GetQuarantined: GET /quarantine?exchange=binance&symbol=BTCUSDT&limit=100
+// Filtreler opsiyoneldir. En son yakalanan mum en üstte.
+func (h *QuarantineHandler) GetQuarantined(c *fiber.Ctx) error {
+	exchange := strings.ToLower(c.Query("exchange"))
+	symbol := ""
+	if raw := c.Query("symbol"); raw != "" {
+		symbol = domain.NormalizeSymbol(raw)
+	}
+	limit := c.QueryInt("limit", 100)
+	if limit <= 0 || limit > 1000 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "limit 1 ile 1000 arasında olmalı"})
+	}
+
+	items, err := h.repo.GetQuarantined(exchange, symbol, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if items == nil {
+		items = []domain.QuarantinedCandle{}
+	}
+	return c.JSON(items)
+}
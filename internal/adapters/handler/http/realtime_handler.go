@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"v2-trading-bot/internal/adapters/auth"
+	"v2-trading-bot/internal/logger"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// Centrifuge taşıyıcılarının yolları (centrifuge-js varsayılanlarıyla aynı).
+const (
+	PathWebsocket  = "/connection/websocket"
+	PathHTTPStream = "/connection/http_stream"
+	PathSSE        = "/connection/sse"
+	// PathEmulation: SSE / HTTP-streaming istemcileri sunucuya komutlarını (subscribe vb.) buraya POST eder.
+	PathEmulation = "/emulation"
+)
+
+// RealtimeConfig: Centrifuge taşıyıcılarının ortak ayarları.
+type RealtimeConfig struct {
+	// AllowedOrigins: Tarayıcı bağlantılarının kabul edildiği sayfalar ("*" hepsine izin verir).
+	AllowedOrigins []string
+	// Authn: Opsiyonel. Varsa Authorization / X-API-Key başlıkları REST'teki gibi doğrulanır;
+	// böylece başlık gönderebilen istemciler (bot, CLI) connect komutunda token vermek zorunda kalmaz.
+	Authn *auth.Authenticator
+}
+
+// MountRealtime: Centrifuge'u mux'a bağlar. WebSocket açılamayan ortamlar (proxy, kurumsal ağ)
+// için SSE ve HTTP-streaming de sunulur; hepsi aynı origin kontrolü, kimlik doğrulama ve logdan geçer.
+func MountRealtime(mux *http.ServeMux, node *centrifuge.Node, cfg RealtimeConfig) {
+	log := logger.For("websocket")
+	wrap := realtimeMiddleware(log, cfg)
+
+	mux.Handle(PathWebsocket, wrap(centrifuge.NewWebsocketHandler(node, centrifuge.WebsocketConfig{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		// Origin realtimeMiddleware'de kontrol edildi.
+		CheckOrigin: func(*http.Request) bool { return true },
+	})))
+	mux.Handle(PathHTTPStream, wrap(centrifuge.NewHTTPStreamHandler(node, centrifuge.HTTPStreamConfig{})))
+	mux.Handle(PathSSE, wrap(centrifuge.NewSSEHandler(node, centrifuge.SSEConfig{})))
+	mux.Handle(PathEmulation, wrap(centrifuge.NewEmulationHandler(node, centrifuge.EmulationConfig{})))
+
+	log.Info("gerçek zamanlı yollar hazır", "paths", []string{PathWebsocket, PathHTTPStream, PathSSE, PathEmulation})
+}
+
+// realtimeMiddleware: Origin kontrolü ve CORS, başlıkla kimlik doğrulama ve bağlantı logu.
+// ResponseWriter sarmalanmaz; WebSocket için Hijacker, SSE için Flusher gerekir.
+func realtimeMiddleware(log *slog.Logger, cfg RealtimeConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if !OriginAllowed(cfg.AllowedOrigins, origin) {
+				log.Warn("bağlantı reddedildi: izin verilmeyen origin", "path", r.URL.Path, "remote", r.RemoteAddr, "origin", origin)
+				http.Error(w, "origin izinli değil", http.StatusForbidden)
+				return
+			}
+			if origin != "" {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Add("Vary", "Origin")
+			}
+			if r.Method == http.MethodOptions {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
+				w.Header().Set("Access-Control-Max-Age", "300")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if cfg.Authn != nil {
+				apiKey := r.Header.Get("X-API-Key")
+				claims, err := verifyHeaders(cfg.Authn, apiKey, r.Header.Get("Authorization"))
+				if err != nil {
+					if apiKey != "" && !errors.Is(err, auth.ErrInvalidCredentials) {
+						log.Error("API anahtarı doğrulanamadı", "err", err)
+						http.Error(w, "kimlik doğrulanamadı", http.StatusInternalServerError)
+						return
+					}
+					log.Info("bağlantı reddedildi: geçersiz kimlik", "path", r.URL.Path, "remote", r.RemoteAddr, "err", err)
+					w.Header().Set("WWW-Authenticate", "Bearer")
+					http.Error(w, err.Error(), http.StatusUnauthorized)
+					return
+				}
+				if claims != nil {
+					r = r.WithContext(auth.NewContext(r.Context(), claims))
+				}
+			}
+
+			// Emülasyon istekleri kısa POST'lardır; sadece uzun ömürlü bağlantılar loglanır.
+			if r.URL.Path == PathEmulation {
+				next.ServeHTTP(w, r)
+				return
+			}
+			start := time.Now()
+			log.Debug("bağlantı açıldı", "path", r.URL.Path, "remote", r.RemoteAddr, "origin", origin)
+			next.ServeHTTP(w, r)
+			log.Debug("bağlantı kapandı", "path", r.URL.Path, "remote", r.RemoteAddr, "duration", time.Since(start))
+		})
+	}
+}
+
+// OriginAllowed: Origin başlığı listede var mı? Tarayıcı dışı istemciler (bot, CLI) Origin
+// göndermez; onlar token ile doğrulanır.
+func OriginAllowed(allowed []string, origin string) bool {
+	if origin == "" {
+		return true
+	}
+	for _, o := range allowed {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"v2-trading-bot/internal/adapters/vault"
+	"v2-trading-bot/internal/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SecretHandler: Borsa API anahtarlarının yönetimi (sadece admin). Yanıtlarda anahtarlar
+// her zaman maskelenir; secret hiç dönmez.
+type SecretHandler struct {
+	vault *vault.Vault
+}
+
+func NewSecretHandler(v *vault.Vault) *SecretHandler {
+	return &SecretHandler{vault: v}
+}
+
+// RegisterRoutes: /admin/secrets rotalarını verilen router'a bağlar.
+func (h *SecretHandler) RegisterRoutes(router fiber.Router) {
+	secrets := router.Group("/admin/secrets", RequireRole(domain.RoleAdmin))
+	secrets.Get("/", h.List)
+	secrets.Post("/rotate", h.Rotate)
+	secrets.Put("/:exchange/:label", h.Put)
+	secrets.Delete("/:exchange/:label", h.Delete)
+}
+
+// List: GET /admin/secrets
+func (h *SecretHandler) List(c *fiber.Ctx) error {
+	items, err := h.vault.List()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(items)
+}
+
+type putSecretRequest struct {
+	APIKey    string `json:"api_key"`
+	APISecret string `json:"api_secret"`
+}
+
+// Put: PUT /admin/secrets/binance/main {"api_key": "...", "api_secret": "..."}
+// Anahtarı ekler veya değiştirir (borsada yenilenen anahtarın kaydı).
+func (h *SecretHandler) Put(c *fiber.Ctx) error {
+	var req putSecretRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "geçersiz istek gövdesi"})
+	}
+	info, err := h.vault.Put(domain.ExchangeCredentials{
+		Exchange:  strings.ToLower(c.Params("exchange")),
+		Label:     c.Params("label"),
+		APIKey:    req.APIKey,
+		APISecret: req.APISecret,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(info)
+}
+
+// Delete: DELETE /admin/secrets/binance/main
+func (h *SecretHandler) Delete(c *fiber.Ctx) error {
+	err := h.vault.Delete(strings.ToLower(c.Params("exchange")), c.Params("label"))
+	if errors.Is(err, domain.ErrSecretNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Rotate: POST /admin/secrets/rotate
+// Eski master anahtarla şifrelenmiş kayıtları güncel anahtarla yeniden şifreler.
+func (h *SecretHandler) Rotate(c *fiber.Ctx) error {
+	rotated, err := h.vault.Rotate()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error(), "rotated": rotated})
+	}
+	return c.JSON(fiber.Map{"rotated": rotated})
+}
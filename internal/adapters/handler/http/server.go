@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// ServerConfig: Ana HTTP sunucusunun ayarları.
+type ServerConfig struct {
+	Addr string
+	// App: REST API. Gerçek zamanlı yollar dışındaki tüm istekler buraya gider.
+	App *fiber.App
+	// Node: Opsiyonel. Varsa Centrifuge taşıyıcıları aynı porttan sunulur.
+	Node     *centrifuge.Node
+	Realtime RealtimeConfig
+}
+
+// NewServer: REST API'yi ve Centrifuge'u tek portta sunan sunucuyu kurar.
+// Fiber fasthttp üzerinde çalışır ve bağlantıyı devralamaz (WebSocket) ya da yanıtı parça
+// parça gönderemez (SSE); bu yüzden dıştaki sunucu net/http'dir ve Fiber adaptörle bağlanır.
+// Kapanışta Shutdown'dan önce Centrifuge kapatılmalıdır, yoksa açık SSE istekleri beklenir.
+func NewServer(cfg ServerConfig) *http.Server {
+	mux := http.NewServeMux()
+	if cfg.Node != nil {
+		MountRealtime(mux, cfg.Node, cfg.Realtime)
+	}
+	mux.Handle("/", limitBody(adaptor.FiberApp(cfg.App), int64(cfg.App.Config().BodyLimit)))
+
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// limitBody: Fiber'ın gövde sınırı adaptörde uygulanmadığı için net/http tarafında uygulanır.
+func limitBody(next http.Handler, limit int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > limit {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StrategyHandler: Stratejiyi başlatma/durdurma ve paper trading cüzdanı için REST uç noktaları.
+// Okuma herkese, değiştirme sadece trader (ve admin) rolüne açıktır. Strateji sadece ingest
+// sürecinde çalışır; api kopyalarında istekler oraya iletilir (ports.StrategyAdmin).
+type StrategyHandler struct {
+	strategy ports.StrategyAdmin
+}
+
+func NewStrategyHandler(strategy ports.StrategyAdmin) *StrategyHandler {
+	return &StrategyHandler{strategy: strategy}
+}
+
+// RegisterRoutes: /strategy ve /wallet rotalarını verilen router'a bağlar.
+func (h *StrategyHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/strategy", h.GetStatus)
+	router.Post("/strategy/start", RequireRole(domain.RoleTrader), h.Start)
+	router.Post("/strategy/stop", RequireRole(domain.RoleTrader), h.Stop)
+
+	router.Get("/wallet", h.GetWallet)
+	router.Post("/wallet/reset", RequireRole(domain.RoleTrader), h.ResetWallet)
+}
+
+// GetStatus: GET /strategy
+func (h *StrategyHandler) GetStatus(c *fiber.Ctx) error {
+	status, err := h.strategy.StrategyStatus(c.UserContext())
+	if err != nil {
+		return strategyError(c, err)
+	}
+	return c.JSON(status)
+}
+
+// Start: POST /strategy/start (trader)
+func (h *StrategyHandler) Start(c *fiber.Ctx) error {
+	status, err := h.strategy.SetStrategyRunning(c.UserContext(), true)
+	if err != nil {
+		return strategyError(c, err)
+	}
+	return c.JSON(status)
+}
+
+// Stop: POST /strategy/stop (trader)
+func (h *StrategyHandler) Stop(c *fiber.Ctx) error {
+	status, err := h.strategy.SetStrategyRunning(c.UserContext(), false)
+	if err != nil {
+		return strategyError(c, err)
+	}
+	return c.JSON(status)
+}
+
+// GetWallet: GET /wallet
+func (h *StrategyHandler) GetWallet(c *fiber.Ctx) error {
+	wallet, err := h.strategy.Wallet(c.UserContext())
+	if err != nil {
+		return strategyError(c, err)
+	}
+	return c.JSON(wallet)
+}
+
+// ResetWallet: POST /wallet/reset (trader)
+// Bakiyeyi başlangıca döndürür; yeni bakiye "wallet:<account>" kanalına da yayınlanır.
+func (h *StrategyHandler) ResetWallet(c *fiber.Ctx) error {
+	wallet, err := h.strategy.ResetWallet(c.UserContext())
+	if err != nil {
+		return strategyError(c, err)
+	}
+	return c.JSON(wallet)
+}
+
+// strategyError: Strateji süreci yoksa 503, yetki yetmezse 403, diğer hatalarda 500.
+func strategyError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrStrategyUnavailable):
+		status = fiber.StatusServiceUnavailable
+	case errors.Is(err, domain.ErrStrategyForbidden):
+		status = fiber.StatusForbidden
+	}
+	return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+}
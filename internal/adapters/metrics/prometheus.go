@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"time"
+	"v2-trading-bot/internal/core/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Tüm metrikler varsayılan Prometheus registry'sine kaydedilir; Centrifuge'ün kendi metrikleri
+// (centrifuge_*) ve Go runtime metrikleri de aynı /metrics çıktısında görünür.
+const namespace = "tradingbot"
+
+var (
+	// --- Borsa akışları ---
+
+	MessagesReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stream_messages_received_total",
+		Help:      "Borsa WebSocket akışlarından alınan ham mesaj sayısı.",
+	}, []string{"stream"})
+
+	StreamSessionsEnded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stream_sessions_ended_total",
+		Help:      "Hatayla biten (ve yeniden bağlanılan) WebSocket oturumu sayısı.",
+	}, []string{"stream"})
+
+	ParseErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stream_parse_errors_total",
+		Help:      "JSON parse veya domain'e çeviri hatası veren mesaj sayısı.",
+	}, []string{"exchange", "kind"})
+
+	// --- İşleme ---
+
+	candleProcessing = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "candle_processing_seconds",
+		Help:      "Kesinleşmiş bir mumun doğrulama, kayıt, yayın ve strateji dahil işlenme süresi.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14), // 0.5ms .. ~4s
+	}, []string{"exchange", "interval"})
+
+	// --- Veritabanı ---
+
+	dbWrite = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_write_seconds",
+		Help:      "Veritabanı yazma işlemlerinin süresi.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+	}, []string{"operation"})
+
+	dbWriteErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_write_errors_total",
+		Help:      "Hatayla biten veritabanı yazma işlemleri.",
+	}, []string{"operation"})
+
+	// --- Strateji / paper trading ---
+
+	signals = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "signals_total",
+		Help:      "Üretilen al/sat sinyalleri.",
+	}, []string{"strategy", "side"})
+
+	paperTrades = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "paper_trades_total",
+		Help:      "Gerçekleşen sanal (paper) işlemler.",
+	}, []string{"side"})
+
+	walletEquity = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "wallet_equity_usdt",
+		Help:      "Sanal cüzdanın son fiyatla USDT karşılığı (USDT + coin * fiyat).",
+	})
+
+	// --- Centrifuge ---
+
+	PublishFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "centrifuge_publish_failures_total",
+		Help:      "Centrifuge kanalına yayınlanamayan mesajlar.",
+	}, []string{"channel"})
+)
+
+// ObserveDBWrite: Yazma süresini ve (varsa) hatayı kaydeder.
+//
+//	start := time.Now()
+//	_, err := r.db.Exec(...)
+//	metrics.ObserveDBWrite("save_candle", start, err)
+func ObserveDBWrite(operation string, start time.Time, err error) {
+	dbWrite.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		dbWriteErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// RegisterClientCount: Bağlı WebSocket istemci sayısını okuyan fonksiyonu gauge olarak kaydeder.
+func RegisterClientCount(count func() int) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "centrifuge_clients",
+		Help:      "Bağlı WebSocket (Centrifuge) istemci sayısı.",
+	}, func() float64 { return float64(count()) })
+}
+
+// Recorder: Core servislerin kullandığı metrikler. ports.Metrics'i implemente eder.
+type Recorder struct{}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// ObserveCandleProcessing: ports.Metrics
+func (Recorder) ObserveCandleProcessing(exchange, interval string, d time.Duration) {
+	candleProcessing.WithLabelValues(exchange, interval).Observe(d.Seconds())
+}
+
+// IncSignal: ports.Metrics
+func (Recorder) IncSignal(strategy string, side domain.SignalType) {
+	signals.WithLabelValues(strategy, string(side)).Inc()
+}
+
+// IncPaperTrade: ports.Metrics
+func (Recorder) IncPaperTrade(side domain.SignalType) {
+	paperTrades.WithLabelValues(string(side)).Inc()
+}
+
+// SetWalletEquity: ports.Metrics
+func (Recorder) SetWalletEquity(usdt float64) {
+	walletEquity.Set(usdt)
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"time"
+	"v2-trading-bot/internal/adapters/metrics"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// SaveOpportunity: ports.ArbitrageRepository
+func (r *Repository) SaveOpportunity(o domain.ArbitrageOpportunity) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := r.db.Exec(ctx, `
+	INSERT INTO arbitrage_opportunities
+		(time, symbol, buy_exchange, sell_exchange, buy_price, sell_price, gross_spread, net_spread, held_seconds, reason)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, o.Timestamp, o.Symbol, o.BuyExchange, o.SellExchange, o.BuyPrice, o.SellPrice, o.GrossSpread, o.NetSpread, o.HeldSeconds, o.Reason)
+	metrics.ObserveDBWrite("save_arbitrage", start, err)
+	return err
+}
+
+// GetOpportunities: ports.ArbitrageRepository. Sonuç en yeniden en eskiye (DESC) sıralıdır.
+func (r *Repository) GetOpportunities(symbol string, limit int) ([]domain.ArbitrageOpportunity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// symbol boşsa ($1 = '') filtre uygulanmaz.
+	rows, err := r.db.Query(ctx, `
+	SELECT id, time, symbol, buy_exchange, sell_exchange, buy_price, sell_price, gross_spread, net_spread, held_seconds, reason
+	FROM arbitrage_opportunities
+	WHERE $1 = '' OR symbol = $1
+	ORDER BY time DESC
+	LIMIT $2
+	`, symbol, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.ArbitrageOpportunity
+	for rows.Next() {
+		var o domain.ArbitrageOpportunity
+		err := rows.Scan(&o.ID, &o.Timestamp, &o.Symbol, &o.BuyExchange, &o.SellExchange,
+			&o.BuyPrice, &o.SellPrice, &o.GrossSpread, &o.NetSpread, &o.HeldSeconds, &o.Reason)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,333 @@
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CandleBatcher: Repository.Save'in "write-behind" versiyonu.
+// Gelen mumları bellekte biriktirir; tampon Size'a ulaşınca ya da FlushInterval dolunca
+// tek bir CopyFrom + upsert ile veritabanına yazar. ports.CandleRepository'i implemente eder.
+//
+// Dayanıklılık:
+//   - Yazma hatasında mumlar kaybolmaz, tampona geri konup bir sonraki turda tekrar denenir.
+//   - Close çağrıldığında tampon boşaltılır; veritabanı yine de yazılamıyorsa mumlar
+//     SpillFile'a dökülür ve bir sonraki açılışta geri yüklenir.
+type CandleBatcher struct {
+	repo          candleWriter
+	size          int
+	maxPending    int
+	flushInterval time.Duration
+	spillFile     string
+	// closeBackoff: Close'daki yazma denemeleri arasında beklenen süre (deneme sayısıyla çarpılır).
+	closeBackoff time.Duration
+
+	mu       sync.Mutex
+	buf      []domain.Candle
+	inflight []domain.Candle // Şu an yazılmakta olan mumlar (okumalar bunları da görmeli)
+	spilled  bool            // Tamponda spill dosyasından gelen mumlar var mı?
+
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// candleWriter: Batcher'ın Repository'den kullandığı kısım (testlerde hata üretebilmek için).
+type candleWriter interface {
+	SaveBatch(ctx context.Context, candles []domain.Candle) error
+	SavePartial(candle domain.Candle) error
+	GetLatestCandles(exchange, symbol, interval string, limit int) ([]domain.Candle, error)
+}
+
+// NewCandleBatcher: Batcher'ı oluşturur ve varsa önceki kapanıştan kalan spill dosyasını yükler.
+// Arka plan döngüsü için Start çağrılmalıdır.
+func NewCandleBatcher(repo *Repository, size int, flushInterval time.Duration, spillFile string) *CandleBatcher {
+	return newCandleBatcher(repo, size, flushInterval, spillFile)
+}
+
+func newCandleBatcher(repo candleWriter, size int, flushInterval time.Duration, spillFile string) *CandleBatcher {
+	if size <= 0 {
+		size = 500
+	}
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+
+	b := &CandleBatcher{
+		repo:          repo,
+		size:          size,
+		maxPending:    size * 20,
+		flushInterval: flushInterval,
+		spillFile:     spillFile,
+		closeBackoff:  time.Second,
+		flushCh:       make(chan struct{}, 1),
+		stopCh:        make(chan struct{}),
+	}
+
+	if err := b.restoreSpill(); err != nil {
+		log.Error("spill dosyası okunamadı", "file", spillFile, "err", err)
+	}
+	return b
+}
+
+// Start: Arka planda periyodik yazma döngüsünü başlatır.
+func (b *CandleBatcher) Start() {
+	b.wg.Add(1)
+	go b.loop()
+}
+
+// Save: Mumu tampona ekler. Veritabanına yazma asenkron yapılır.
+// Tampon kapasitesi aşılırsa (veritabanı uzun süredir yazılamıyorsa) hata döner.
+func (b *CandleBatcher) Save(candle domain.Candle) error {
+	select {
+	case <-b.stopCh:
+		return errors.New("candle batcher kapatıldı")
+	default:
+	}
+
+	b.mu.Lock()
+	if len(b.buf) >= b.maxPending {
+		b.mu.Unlock()
+		return fmt.Errorf("candle tamponu dolu (%d bekleyen mum)", b.maxPending)
+	}
+	b.buf = append(b.buf, candle)
+	full := len(b.buf) >= b.size
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// SavePartial: Canlı mumlar tampona alınmaz, doğrudan yazılır (satır başına tek upsert).
+func (b *CandleBatcher) SavePartial(candle domain.Candle) error {
+	return b.repo.SavePartial(candle)
+}
+
+// GetLatestCandles: Veritabanındaki mumları, henüz yazılmamış tampondakilerle birleştirip döner.
+// Böylece strateji, az önce Save edilen mumu da görür.
+func (b *CandleBatcher) GetLatestCandles(exchange, symbol, interval string, limit int) ([]domain.Candle, error) {
+	stored, err := b.repo.GetLatestCandles(exchange, symbol, interval, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	pending := make([]domain.Candle, 0, len(b.inflight)+len(b.buf))
+	pending = append(pending, b.inflight...)
+	pending = append(pending, b.buf...)
+	b.mu.Unlock()
+
+	merged := make(map[candleKey]domain.Candle, len(stored))
+	for _, c := range stored {
+		merged[keyOf(c)] = c
+	}
+	for _, c := range pending {
+		if c.Exchange == exchange && c.Symbol == symbol && c.Interval == interval {
+			merged[keyOf(c)] = c
+		}
+	}
+
+	out := make([]domain.Candle, 0, len(merged))
+	for _, c := range merged {
+		out = append(out, c)
+	}
+	// Repository ile aynı sözleşme: en yeni en üstte (DESC).
+	sort.Slice(out, func(i, j int) bool { return out[i].OpenTime.After(out[j].OpenTime) })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// Close: Döngüyü durdurur ve tamponda kalan her şeyi yazar.
+// Yazılamayan mumlar spill dosyasına dökülür.
+func (b *CandleBatcher) Close() error {
+	close(b.stopCh)
+	b.wg.Wait()
+
+	var err error
+	for attempt := 1; attempt <= 3; attempt++ {
+		if err = b.flush(); err == nil {
+			return nil
+		}
+		log.Warn("kapanışta yazma denemesi başarısız", "attempt", attempt, "err", err)
+		time.Sleep(time.Duration(attempt) * b.closeBackoff)
+	}
+
+	if spillErr := b.spill(); spillErr != nil {
+		return errors.Join(err, spillErr)
+	}
+	return nil
+}
+
+func (b *CandleBatcher) loop() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopCh:
+			return
+		case <-ticker.C:
+		case <-b.flushCh:
+		}
+		if err := b.flush(); err != nil {
+			log.Warn("toplu yazma hatası (tekrar denenecek)", "err", err)
+		}
+	}
+}
+
+// flush: Tamponu alıp tek seferde yazar. Hata olursa mumları tamponun başına geri koyar.
+func (b *CandleBatcher) flush() error {
+	b.mu.Lock()
+	if len(b.buf) == 0 {
+		b.mu.Unlock()
+		return nil
+	}
+	batch := dedupeCandles(b.buf)
+	b.buf = nil
+	b.inflight = batch
+	spilled := b.spilled
+	b.mu.Unlock()
+
+	// Mumlar farklı receive span'larından gelir; flush kendi kökü olan ayrı bir trace'tir.
+	ctx, span := tracer.Start(context.Background(), "candles flush", trace.WithAttributes(
+		attribute.Int("candles", len(batch)),
+		attribute.Bool("spilled", spilled),
+	))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	err := b.repo.SaveBatch(ctx, batch)
+	cancel()
+	endSpan(span, err)
+
+	b.mu.Lock()
+	b.inflight = nil
+	if err != nil {
+		b.buf = append(batch, b.buf...)
+	} else if spilled {
+		b.spilled = false
+	}
+	b.mu.Unlock()
+
+	if err == nil && spilled {
+		// Spill'den gelen mumlar artık veritabanında, dosyayı silebiliriz.
+		os.Remove(b.spillFile)
+	}
+	return err
+}
+
+// spill: Tampondaki mumları JSON satırları olarak dosyaya yazar.
+func (b *CandleBatcher) spill() error {
+	b.mu.Lock()
+	pending := b.buf
+	b.mu.Unlock()
+
+	if len(pending) == 0 || b.spillFile == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(b.spillFile), 0o755); err != nil {
+		return err
+	}
+
+	// Önceki spill'den gelenler hâlâ yazılamadıysa zaten tampondalar, dosyayı baştan yazabiliriz.
+	f, err := os.OpenFile(b.spillFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, c := range pending {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	log.Warn("mumlar spill dosyasına yazıldı", "candles", len(pending), "file", b.spillFile)
+	return f.Sync()
+}
+
+// restoreSpill: Önceki kapanıştan kalan mumları tampona yükler.
+func (b *CandleBatcher) restoreSpill() error {
+	if b.spillFile == "" {
+		return nil
+	}
+	f, err := os.Open(b.spillFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var restored []domain.Candle
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var c domain.Candle
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return err
+		}
+		if c.Exchange == "" {
+			// Çoklu borsa desteğinden önce dökülmüş mumlar.
+			c.Exchange = domain.ExchangeBinance
+		}
+		restored = append(restored, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	b.buf = append(restored, b.buf...)
+	b.spilled = len(restored) > 0
+	log.Info("spill dosyasından mumlar geri yüklendi", "candles", len(restored))
+	return nil
+}
+
+// candleKey: candles tablosunun birincil anahtarı. Zaman mikrosaniye çözünürlüğündedir (Postgres
+// timestamp'i gibi); eşik barları aynı milisaniyede 1µs arayla açılabilir.
+type candleKey struct {
+	time     int64
+	exchange string
+	symbol   string
+	interval string
+}
+
+func keyOf(c domain.Candle) candleKey {
+	return candleKey{time: c.OpenTime.UnixMicro(), exchange: c.Exchange, symbol: c.Symbol, interval: c.Interval}
+}
+
+// dedupeCandles: Aynı anahtara sahip mumlardan sonuncusunu tutar.
+// Tek bir INSERT içinde aynı satıra iki kez dokunmak Postgres'te hata verir.
+func dedupeCandles(candles []domain.Candle) []domain.Candle {
+	index := make(map[candleKey]int, len(candles))
+	out := make([]domain.Candle, 0, len(candles))
+	for _, c := range candles {
+		k := keyOf(c)
+		if i, ok := index[k]; ok {
+			out[i] = c
+			continue
+		}
+		index[k] = len(out)
+		out = append(out, c)
+	}
+	return out
+}
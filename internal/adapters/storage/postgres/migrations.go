@@ -0,0 +1,350 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migration: Şemaya uygulanacak tek bir değişiklik.
+// Version sırası önemlidir; bir kere uygulanan migration tekrar çalıştırılmaz.
+type migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// migrations: Şema geçmişi. Yeni bir değişiklik gerekiyorsa SONUNA yeni bir eleman ekle,
+// mevcut olanları asla değiştirme (uygulanmış veritabanlarında tekrar çalışmazlar).
+var migrations = []migration{
+	{
+		Version: 1,
+		Name:    "create_candles",
+		SQL: `
+		CREATE TABLE IF NOT EXISTS candles (
+			time        TIMESTAMPTZ NOT NULL,
+			symbol      TEXT NOT NULL,
+			interval    TEXT NOT NULL,
+			open        DOUBLE PRECISION,
+			high        DOUBLE PRECISION,
+			low         DOUBLE PRECISION,
+			close       DOUBLE PRECISION,
+			volume      DOUBLE PRECISION,
+			PRIMARY KEY (time, symbol, interval)
+		);
+		SELECT create_hypertable('candles', 'time', if_not_exists => TRUE);
+		`,
+	},
+	{
+		// Sıkıştırma: symbol/interval bazında segmentlere ayır, zamana göre sırala.
+		// Saklama: TimescaleDB'nin add_retention_policy'si tüm tabloya uygulanır,
+		// biz interval bazında farklı süreler istediğimiz için kendi job'umuzu yazıyoruz.
+		Version: 2,
+		Name:    "candles_compression_and_retention",
+		SQL: `
+		ALTER TABLE candles SET (
+			timescaledb.compress,
+			timescaledb.compress_segmentby = 'symbol, interval',
+			timescaledb.compress_orderby = 'time DESC'
+		);
+
+		CREATE TABLE IF NOT EXISTS candle_retention_policies (
+			interval TEXT PRIMARY KEY,
+			keep_for INTERVAL NOT NULL
+		);
+
+		CREATE OR REPLACE PROCEDURE candles_apply_retention(job_id INT, config JSONB)
+		LANGUAGE plpgsql AS $$
+		DECLARE
+			p RECORD;
+		BEGIN
+			FOR p IN SELECT "interval", keep_for FROM candle_retention_policies LOOP
+				DELETE FROM candles WHERE "interval" = p."interval" AND time < NOW() - p.keep_for;
+				COMMIT;
+			END LOOP;
+		END
+		$$;
+
+		SELECT add_job('candles_apply_retention', '1 hour')
+		WHERE NOT EXISTS (
+			SELECT 1 FROM timescaledb_information.jobs WHERE proc_name = 'candles_apply_retention'
+		);
+		`,
+	},
+	{
+		// Mumlar artık kline'ın açılış zamanıyla (t) anahtarlanıyor; kapanış zamanı da saklanıyor.
+		// candles_live: henüz kapanmamış (in-progress) mumların son hali, sembol/interval başına tek satır.
+		Version: 3,
+		Name:    "candles_close_time_and_live",
+		SQL: `
+		ALTER TABLE candles ADD COLUMN IF NOT EXISTS close_time TIMESTAMPTZ;
+
+		CREATE TABLE IF NOT EXISTS candles_live (
+			symbol      TEXT NOT NULL,
+			interval    TEXT NOT NULL,
+			time        TIMESTAMPTZ NOT NULL,
+			close_time  TIMESTAMPTZ,
+			open        DOUBLE PRECISION,
+			high        DOUBLE PRECISION,
+			low         DOUBLE PRECISION,
+			close       DOUBLE PRECISION,
+			volume      DOUBLE PRECISION,
+			updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (symbol, interval)
+		);
+		`,
+	},
+	{
+		// Hacim bazlı stratejiler için Binance kline'ının tüm alanları.
+		Version: 4,
+		Name:    "candles_volume_fields",
+		SQL: `
+		ALTER TABLE candles
+			ADD COLUMN IF NOT EXISTS quote_volume           DOUBLE PRECISION,
+			ADD COLUMN IF NOT EXISTS trade_count            BIGINT,
+			ADD COLUMN IF NOT EXISTS taker_buy_base_volume  DOUBLE PRECISION,
+			ADD COLUMN IF NOT EXISTS taker_buy_quote_volume DOUBLE PRECISION,
+			ADD COLUMN IF NOT EXISTS first_trade_id         BIGINT,
+			ADD COLUMN IF NOT EXISTS last_trade_id          BIGINT;
+
+		ALTER TABLE candles_live
+			ADD COLUMN IF NOT EXISTS quote_volume           DOUBLE PRECISION,
+			ADD COLUMN IF NOT EXISTS trade_count            BIGINT,
+			ADD COLUMN IF NOT EXISTS taker_buy_base_volume  DOUBLE PRECISION,
+			ADD COLUMN IF NOT EXISTS taker_buy_quote_volume DOUBLE PRECISION,
+			ADD COLUMN IF NOT EXISTS first_trade_id         BIGINT,
+			ADD COLUMN IF NOT EXISTS last_trade_id          BIGINT;
+		`,
+	},
+	{
+		// aggTrade akışı: tick verisi çok hızlı büyüdüğü için günlük chunk'lar ve erken sıkıştırma.
+		Version: 5,
+		Name:    "create_trades",
+		SQL: `
+		CREATE TABLE IF NOT EXISTS trades (
+			time            TIMESTAMPTZ NOT NULL,
+			symbol          TEXT NOT NULL,
+			agg_trade_id    BIGINT NOT NULL,
+			price           DOUBLE PRECISION NOT NULL,
+			quantity        DOUBLE PRECISION NOT NULL,
+			first_trade_id  BIGINT,
+			last_trade_id   BIGINT,
+			is_buyer_maker  BOOLEAN NOT NULL,
+			PRIMARY KEY (time, symbol, agg_trade_id)
+		);
+		SELECT create_hypertable('trades', 'time', chunk_time_interval => INTERVAL '1 day', if_not_exists => TRUE);
+
+		ALTER TABLE trades SET (
+			timescaledb.compress,
+			timescaledb.compress_segmentby = 'symbol',
+			timescaledb.compress_orderby = 'time DESC, agg_trade_id DESC'
+		);
+		SELECT add_compression_policy('trades', compress_after => INTERVAL '2 days', if_not_exists => TRUE);
+		`,
+	},
+	{
+		// Birden fazla borsa: aynı sembol/interval/zaman artık borsa bazında ayrı satırdır.
+		// Sıkıştırılmış chunk varken segmentby değiştirilemediği için önce hepsi açılır;
+		// sıkıştırma politikası onları daha sonra tekrar sıkıştırır.
+		Version: 6,
+		Name:    "candles_exchange",
+		SQL: `
+		SELECT decompress_chunk(c, if_compressed => TRUE) FROM show_chunks('candles') c;
+
+		ALTER TABLE candles ADD COLUMN IF NOT EXISTS exchange TEXT NOT NULL DEFAULT 'binance';
+		ALTER TABLE candles DROP CONSTRAINT IF EXISTS candles_pkey;
+		ALTER TABLE candles ADD PRIMARY KEY (time, exchange, symbol, interval);
+		ALTER TABLE candles SET (timescaledb.compress_segmentby = 'exchange, symbol, interval');
+
+		ALTER TABLE candles_live ADD COLUMN IF NOT EXISTS exchange TEXT NOT NULL DEFAULT 'binance';
+		ALTER TABLE candles_live DROP CONSTRAINT IF EXISTS candles_live_pkey;
+		ALTER TABLE candles_live ADD PRIMARY KEY (exchange, symbol, interval);
+		`,
+	},
+	{
+		Version: 7,
+		Name:    "create_arbitrage_opportunities",
+		SQL: `
+		CREATE TABLE IF NOT EXISTS arbitrage_opportunities (
+			id            BIGSERIAL PRIMARY KEY,
+			time          TIMESTAMPTZ NOT NULL,
+			symbol        TEXT NOT NULL,
+			buy_exchange  TEXT NOT NULL,
+			sell_exchange TEXT NOT NULL,
+			buy_price     DOUBLE PRECISION NOT NULL,
+			sell_price    DOUBLE PRECISION NOT NULL,
+			gross_spread  DOUBLE PRECISION NOT NULL,
+			net_spread    DOUBLE PRECISION NOT NULL,
+			held_seconds  DOUBLE PRECISION NOT NULL,
+			reason        TEXT NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS arbitrage_opportunities_symbol_time_idx ON arbitrage_opportunities (symbol, time DESC);
+		`,
+	},
+	{
+		// Doğrulamadan geçemeyen mumlar. Mumun tamamı JSONB olarak saklanır (bozuk değerler dahil).
+		Version: 8,
+		Name:    "create_candle_quarantine",
+		SQL: `
+		CREATE TABLE IF NOT EXISTS candle_quarantine (
+			id          BIGSERIAL PRIMARY KEY,
+			detected_at TIMESTAMPTZ NOT NULL,
+			exchange    TEXT NOT NULL,
+			symbol      TEXT NOT NULL,
+			interval    TEXT NOT NULL,
+			open_time   TIMESTAMPTZ NOT NULL,
+			severity    TEXT NOT NULL,
+			reasons     TEXT[] NOT NULL,
+			candle      JSONB NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS candle_quarantine_detected_idx ON candle_quarantine (detected_at DESC);
+		`,
+	},
+	{
+		// Dashboard/API kullanıcıları. Şifre bcrypt, API anahtarı SHA-256 özeti olarak saklanır.
+		Version: 9,
+		Name:    "create_users",
+		SQL: `
+		CREATE TABLE IF NOT EXISTS users (
+			username      TEXT PRIMARY KEY,
+			password_hash TEXT NOT NULL,
+			role          TEXT NOT NULL,
+			accounts      TEXT[] NOT NULL DEFAULT '{}',
+			api_key_hash  TEXT UNIQUE,
+			created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		`,
+	},
+	{
+		// Borsa API anahtarları. Değerler uygulamada AES-GCM ile şifrelenir; veritabanı düz metni hiç görmez.
+		Version: 10,
+		Name:    "create_exchange_secrets",
+		SQL: `
+		CREATE TABLE IF NOT EXISTS exchange_secrets (
+			exchange       TEXT NOT NULL,
+			label          TEXT NOT NULL,
+			key_version    INT NOT NULL,
+			api_key_enc    BYTEA NOT NULL,
+			api_secret_enc BYTEA NOT NULL,
+			api_key_hint   TEXT NOT NULL,
+			created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (exchange, label)
+		);
+		`,
+	},
+	{
+		// Saklama job'u chunk bazında çalışır. drop_chunks interval'a göre süzemez (tüm interval'lar
+		// aynı chunk'ları paylaşır), bu yüzden silme yine DELETE ile yapılır ama:
+		//   - Tamamı süresi dolmuş chunk'ta sadece segmentby kolonuna (interval) göre silinir;
+		//     sıkıştırılmış batch'ler satır satır açılmadan atılır.
+		//   - Sınırdaki chunk'ta zaman koşulu da eklenir (genelde henüz sıkıştırılmamıştır).
+		//   - Tüm interval'ları silinip boş kalan chunk tamamen kaldırılır.
+		Version: 11,
+		Name:    "candles_retention_by_chunk",
+		SQL: `
+		CREATE OR REPLACE PROCEDURE candles_apply_retention(job_id INT, config JSONB)
+		LANGUAGE plpgsql AS $$
+		DECLARE
+			p      RECORD;
+			c      RECORD;
+			cutoff TIMESTAMPTZ;
+			empty  BOOLEAN;
+		BEGIN
+			FOR p IN SELECT "interval", keep_for FROM candle_retention_policies LOOP
+				cutoff := NOW() - p.keep_for;
+				FOR c IN
+					SELECT format('%I.%I', chunk_schema, chunk_name) AS chunk, range_end
+					FROM timescaledb_information.chunks
+					WHERE hypertable_name = 'candles' AND range_start < cutoff
+				LOOP
+					IF c.range_end <= cutoff THEN
+						EXECUTE format('DELETE FROM %s WHERE "interval" = $1', c.chunk) USING p."interval";
+					ELSE
+						EXECUTE format('DELETE FROM %s WHERE "interval" = $1 AND time < $2', c.chunk)
+							USING p."interval", cutoff;
+					END IF;
+					COMMIT;
+				END LOOP;
+			END LOOP;
+
+			FOR c IN
+				SELECT format('%I.%I', chunk_schema, chunk_name) AS chunk
+				FROM timescaledb_information.chunks
+				WHERE hypertable_name = 'candles'
+				  AND range_end <= NOW() - (SELECT min(keep_for) FROM candle_retention_policies)
+			LOOP
+				EXECUTE format('SELECT NOT EXISTS (SELECT 1 FROM %s)', c.chunk) INTO empty;
+				IF empty THEN
+					EXECUTE format('DROP TABLE %s', c.chunk);
+					COMMIT;
+				END IF;
+			END LOOP;
+		END
+		$$;
+		`,
+	},
+}
+
+// migrationLock: pg_advisory_xact_lock anahtarı. Aynı anda başlayan süreçler (api kopyaları ve
+// ingest) migration'ları sırayla uygular; kilit transaction bitince kendiliğinden bırakılır.
+const migrationLock = 7_240_001
+
+// runMigrations: Henüz uygulanmamış migration'ları sırayla, her biri kendi transaction'ında çalıştırır.
+func runMigrations(ctx context.Context, pool *pgxpool.Pool) error {
+	err := lockedTx(ctx, pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INT PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		`)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("schema_migrations tablosu oluşturulamadı: %w", err)
+	}
+
+	for _, m := range migrations {
+		applied := false
+		err := lockedTx(ctx, pool, func(tx pgx.Tx) error {
+			// Geçmiş kilit alındıktan sonra okunur: başka bir süreç bu migration'ı az önce uygulamış olabilir.
+			var done bool
+			if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, m.Version).Scan(&done); err != nil {
+				return fmt.Errorf("migration geçmişi okunamadı: %w", err)
+			}
+			if done {
+				return nil
+			}
+			if _, err := tx.Exec(ctx, m.SQL); err != nil {
+				return fmt.Errorf("migration %d (%s) başarısız: %w", m.Version, m.Name, err)
+			}
+			if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name); err != nil {
+				return err
+			}
+			applied = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if applied {
+			log.Info("migration uygulandı", "version", m.Version, "name", m.Name)
+		}
+	}
+
+	return nil
+}
+
+// lockedTx: fn'i migrationLock altında tek bir transaction'da çalıştırır; fn hata dönerse geri alınır.
+func lockedTx(ctx context.Context, pool *pgxpool.Pool, fn func(tx pgx.Tx) error) error {
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLock); err != nil {
+			return fmt.Errorf("migration kilidi alınamadı: %w", err)
+		}
+		return fn(tx)
+	})
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"v2-trading-bot/internal/adapters/metrics"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// SaveQuarantined: ports.QuarantineRepository
+func (r *Repository) SaveQuarantined(q domain.QuarantinedCandle) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	candle, err := json.Marshal(q.Candle)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	_, err = r.db.Exec(ctx, `
+	INSERT INTO candle_quarantine (detected_at, exchange, symbol, interval, open_time, severity, reasons, candle)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, q.DetectedAt, q.Candle.Exchange, q.Candle.Symbol, q.Candle.Interval, q.Candle.OpenTime,
+		string(q.Severity), q.Reasons, candle)
+	metrics.ObserveDBWrite("save_quarantine", start, err)
+	return err
+}
+
+// GetQuarantined: ports.QuarantineRepository. Sonuç en yeniden en eskiye (DESC) sıralıdır.
+func (r *Repository) GetQuarantined(exchange, symbol string, limit int) ([]domain.QuarantinedCandle, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx, `
+	SELECT id, detected_at, severity, reasons, candle
+	FROM candle_quarantine
+	WHERE ($1 = '' OR exchange = $1) AND ($2 = '' OR symbol = $2)
+	ORDER BY detected_at DESC
+	LIMIT $3
+	`, exchange, symbol, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.QuarantinedCandle
+	for rows.Next() {
+		var (
+			q        domain.QuarantinedCandle
+			severity string
+			candle   []byte
+		)
+		if err := rows.Scan(&q.ID, &q.DetectedAt, &severity, &q.Reasons, &candle); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(candle, &q.Candle); err != nil {
+			return nil, err
+		}
+		q.Severity = domain.QualitySeverity(severity)
+		out = append(out, q)
+	}
+	return out, rows.Err()
+}
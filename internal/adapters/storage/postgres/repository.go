@@ -3,12 +3,20 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
+	"v2-trading-bot/internal/adapters/metrics"
 	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/logger"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var log = logger.For("storage")
+
 // Repository: TimescaleDB ile konusan adaptörümüz.
 // ports.CandleRepository interface'ini implemente eder.
 type Repository struct {
@@ -23,34 +31,9 @@ func NewRepository(connString string) (*Repository, error) {
 		return nil, fmt.Errorf("DB bağlantı hatası: %w", err)
 	}
 
-	// Veritabanı tablo oluşturma
-	// TimescaleDB için "event_time" kolonu önemlidir.
-	query := `CREATE TABLE IF NOT EXISTS candles (
-		time        TIMESTAMPTZ NOT NULL,
-		symbol      TEXT NOT NULL,
-		interval    TEXT NOT NULL,
-		open        DOUBLE PRECISION,
-		high        DOUBLE PRECISION,
-		low         DOUBLE PRECISION,
-		close       DOUBLE PRECISION,
-		volume      DOUBLE PRECISION,
-		PRIMARY KEY (time, symbol, interval)
-	);
-	`
-
-	_, err = pool.Exec(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("Tablo oluşturma hatası: %w", err)
-	}
-
-	// Tabloyu HyperTable'a çeviriyoruz (timescaleDB büyüsü imiş)
-	// Bu komut standart Postgres tablosunu zaman serisi tablosuna çevirir.
-	// Hata verirse (zaten hypertable ise) yoksayabiliriz ama temiz kod için kontrol etmek iyidir.
-	// Basitlik adına direkt çalıştırıyoruz, hata verirse "zaten hypertable" diye varsayıyoruz şimdilik.
-	hypertableQuery := `SELECT create_hypertable('candles','time', if_not_exists => TRUE);`
-	_, err = pool.Exec(ctx, hypertableQuery)
-	if err != nil {
-		fmt.Printf("Hypertable uyarısı (önemli olmayabilir): %v\n", err)
+	// Şemayı migration'lar üzerinden hazırlıyoruz (candles hypertable, sıkıştırma, retention job'u).
+	if err := runMigrations(ctx, pool); err != nil {
+		return nil, fmt.Errorf("migration hatası: %w", err)
 	}
 
 	// Cüzdan Tablosunu Hazırla (Paper Trading)
@@ -60,49 +43,160 @@ func NewRepository(connString string) (*Repository, error) {
 	return &Repository{db: pool}, nil
 }
 
-// Save : Tek bir mumu veritabanına yazar.
+// CheckHealth: ports.HealthCheck. Bağlantı havuzundan bir bağlantı alıp ping atar.
+func (r *Repository) CheckHealth(ctx context.Context) error {
+	return r.db.Ping(ctx)
+}
+
+// candleColumns: candles tablosuna yazılan kolonlar. candleRow ile aynı sırada olmalı.
+var candleColumns = []string{
+	"time", "exchange", "symbol", "interval", "close_time",
+	"open", "high", "low", "close", "volume",
+	"quote_volume", "trade_count", "taker_buy_base_volume", "taker_buy_quote_volume",
+	"first_trade_id", "last_trade_id",
+}
+
+var (
+	// candleColumnList: "time, symbol, interval, ..." şeklinde kolon listesi.
+	candleColumnList = strings.Join(candleColumns, ", ")
+
+	// candleUpsert: Aynı açılış zamanına sahip mum tekrar gelirse (Binance düzeltmesi) üzerine yazar.
+	candleUpsert = "ON CONFLICT (time, exchange, symbol, interval) DO UPDATE SET " + excludedSet(candleColumns[4:])
+)
+
+// candleRow: domain nesnesini candleColumns sırasına göre sql parametrelerine çevirir.
+func candleRow(c domain.Candle) []any {
+	return []any{
+		c.OpenTime,
+		c.Exchange,
+		c.Symbol,
+		c.Interval,
+		c.CloseTime,
+		c.Open,
+		c.High,
+		c.Low,
+		c.Close,
+		c.Volume,
+		c.QuoteVolume,
+		c.TradeCount,
+		c.TakerBuyBaseVolume,
+		c.TakerBuyQuoteVolume,
+		c.FirstTradeID,
+		c.LastTradeID,
+	}
+}
+
+// Save : Tek bir mumu veritabanına yazar. Mum zaten varsa günceller.
 func (r *Repository) Save(candle domain.Candle) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `
-	INSERT INTO candles (time, symbol, interval, open, high, low, close, volume)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	ON CONFLICT DO NOTHING;
-	`
+	query := fmt.Sprintf(`INSERT INTO candles (%s) VALUES (%s) %s`,
+		candleColumnList, placeholders(len(candleColumns)), candleUpsert)
 
-	// domain nesnesini sql parametrelerine çeviriyoruz
-	_, err := r.db.Exec(ctx, query,
-		candle.EventTime,
-		candle.Symbol,
-		candle.Interval,
-		candle.Open,
-		candle.High,
-		candle.Low,
-		candle.Close,
-		candle.Volume,
-	)
+	start := time.Now()
+	_, err := r.db.Exec(ctx, query, candleRow(candle)...)
+	metrics.ObserveDBWrite("save_candle", start, err)
 	if err != nil {
 		return fmt.Errorf("Kayıt hatası: %w", err)
 	}
 	return nil
 }
 
-// GetLastCandles: Strateji hesaplaması için geçmiş veriyi çeker.
-func (r *Repository) GetLatestCandles(symbol string, limit int) ([]domain.Candle, error) {
+// SaveBatch: Birden fazla mumu tek seferde yazar.
+// Mumlar önce CopyFrom ile geçici bir staging tablosuna basılır, oradan tek bir
+// INSERT ... SELECT ile asıl tabloya aktarılır. Böylece upsert davranışı Save ile aynı kalır.
+// Not: Aynı anahtar batch içinde iki kez olmamalı (CandleBatcher bunu dedupe eder).
+func (r *Repository) SaveBatch(ctx context.Context, candles []domain.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "save_batch", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("db.system", "postgresql"),
+		attribute.Int("candles", len(candles)),
+	))
+	start := time.Now()
+	err := r.saveBatch(ctx, candles)
+	metrics.ObserveDBWrite("save_batch", start, err)
+	endSpan(span, err)
+	return err
+}
+
+func (r *Repository) saveBatch(ctx context.Context, candles []domain.Candle) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("batch transaction açılamadı: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `CREATE TEMP TABLE candles_staging (LIKE candles INCLUDING DEFAULTS) ON COMMIT DROP`)
+	if err != nil {
+		return fmt.Errorf("staging tablosu oluşturulamadı: %w", err)
+	}
+
+	rows := make([][]any, 0, len(candles))
+	for _, c := range candles {
+		rows = append(rows, candleRow(c))
+	}
+	copyCtx, copySpan := tracer.Start(ctx, "copy candles_staging")
+	_, err = tx.CopyFrom(copyCtx, pgx.Identifier{"candles_staging"}, candleColumns, pgx.CopyFromRows(rows))
+	endSpan(copySpan, err)
+	if err != nil {
+		return fmt.Errorf("CopyFrom hatası: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`INSERT INTO candles (%s) SELECT %s FROM candles_staging %s`,
+		candleColumnList, candleColumnList, candleUpsert))
+	if err != nil {
+		return fmt.Errorf("staging aktarım hatası: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SavePartial: Henüz kapanmamış mumun son halini candles_live tablosuna yazar.
+// Sembol/interval başına tek satır tutulur, asıl candles tablosu kirlenmez.
+// Sırası bozulmuş eski güncelleme (önceki dakika veya daha az işlemli hal) yenisinin üzerine yazılmaz.
+func (r *Repository) SavePartial(candle domain.Candle) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+	INSERT INTO candles_live (%s, updated_at) VALUES (%s, NOW())
+	ON CONFLICT (exchange, symbol, interval) DO UPDATE SET time = EXCLUDED.time, %s, updated_at = NOW()
+	WHERE (candles_live.time, COALESCE(candles_live.trade_count, 0)) <= (EXCLUDED.time, EXCLUDED.trade_count)`,
+		candleColumnList, placeholders(len(candleColumns)), excludedSet(candleColumns[4:]))
+
+	start := time.Now()
+	_, err := r.db.Exec(ctx, query, candleRow(candle)...)
+	metrics.ObserveDBWrite("save_partial", start, err)
+	if err != nil {
+		return fmt.Errorf("canlı mum kayıt hatası: %w", err)
+	}
+	return nil
+}
+
+// GetLatestCandles: Strateji hesaplaması ve REST API için geçmiş veriyi çeker.
+// Sonuç en yeniden en eskiye (DESC) sıralıdır.
+func (r *Repository) GetLatestCandles(exchange, symbol, interval string, limit int) ([]domain.Candle, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// Zamanı Ters çevirip (desc) son gelenleri alıyoruz, sonra tekrar düzeltmek gerekebilir, şimdilik en yenileri en üste geliyor.
+	// Eski satırlarda yeni kolonlar NULL olabilir, Scan patlamasın diye COALESCE ediyoruz.
 	query := `
-	SELECT time, symbol, interval, open, high, low, close, volume
+	SELECT time, exchange, symbol, interval, COALESCE(close_time, time),
+	       open, high, low, close, volume,
+	       COALESCE(quote_volume, 0), COALESCE(trade_count, 0),
+	       COALESCE(taker_buy_base_volume, 0), COALESCE(taker_buy_quote_volume, 0),
+	       COALESCE(first_trade_id, 0), COALESCE(last_trade_id, 0)
 	FROM candles
-	WHERE symbol = $1
+	WHERE exchange = $1 AND symbol = $2 AND interval = $3
 	ORDER BY time DESC
-	LIMIT $2
+	LIMIT $4
 	`
 
-	rows, err := r.db.Query(ctx, query, symbol, limit)
+	rows, err := r.db.Query(ctx, query, exchange, symbol, interval, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -112,19 +206,46 @@ func (r *Repository) GetLatestCandles(symbol string, limit int) ([]domain.Candle
 	for rows.Next() {
 		var c domain.Candle
 		err := rows.Scan(
-			&c.EventTime,
+			&c.OpenTime,
+			&c.Exchange,
 			&c.Symbol,
 			&c.Interval,
+			&c.CloseTime,
 			&c.Open,
 			&c.High,
 			&c.Low,
 			&c.Close,
 			&c.Volume,
+			&c.QuoteVolume,
+			&c.TradeCount,
+			&c.TakerBuyBaseVolume,
+			&c.TakerBuyQuoteVolume,
+			&c.FirstTradeID,
+			&c.LastTradeID,
 		)
 		if err != nil {
 			return nil, err
 		}
+		c.IsClosed = true
 		candles = append(candles, c)
 	}
-	return candles, nil
+	return candles, rows.Err()
+}
+
+// placeholders: "$1, $2, ..., $n"
+func placeholders(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = fmt.Sprintf("$%d", i+1)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// excludedSet: "col = EXCLUDED.col, ..." (ON CONFLICT DO UPDATE için)
+func excludedSet(columns []string) string {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = fmt.Sprintf("%s = EXCLUDED.%s", col, col)
+	}
+	return strings.Join(parts, ", ")
 }
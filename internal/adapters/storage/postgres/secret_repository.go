@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+	"v2-trading-bot/internal/adapters/metrics"
+	"v2-trading-bot/internal/core/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const secretColumns = `exchange, label, key_version, api_key_enc, api_secret_enc, api_key_hint, created_at, updated_at`
+
+// SaveSecret: ports.SecretRepository. Varsa üzerine yazar (created_at korunur).
+func (r *Repository) SaveSecret(s domain.EncryptedSecret) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := r.db.Exec(ctx, `
+	INSERT INTO exchange_secrets (`+secretColumns+`)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (exchange, label) DO UPDATE SET
+		key_version    = EXCLUDED.key_version,
+		api_key_enc    = EXCLUDED.api_key_enc,
+		api_secret_enc = EXCLUDED.api_secret_enc,
+		api_key_hint   = EXCLUDED.api_key_hint,
+		updated_at     = EXCLUDED.updated_at
+	`, s.Exchange, s.Label, s.KeyVersion, s.APIKey, s.APISecret, s.APIKeyHint, s.CreatedAt, s.UpdatedAt)
+	metrics.ObserveDBWrite("save_secret", start, err)
+	return err
+}
+
+// ReplaceSecret: ports.SecretRepository. Koşullu UPDATE: arada yazılan yeni anahtar geri alınmaz.
+func (r *Repository) ReplaceSecret(s, previous domain.EncryptedSecret) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	tag, err := r.db.Exec(ctx, `
+	UPDATE exchange_secrets SET
+		key_version    = $3,
+		api_key_enc    = $4,
+		api_secret_enc = $5,
+		api_key_hint   = $6,
+		updated_at     = $7
+	WHERE exchange = $1 AND label = $2
+	  AND key_version = $8 AND updated_at = $9
+	`, s.Exchange, s.Label, s.KeyVersion, s.APIKey, s.APISecret, s.APIKeyHint, s.UpdatedAt,
+		previous.KeyVersion, previous.UpdatedAt)
+	metrics.ObserveDBWrite("replace_secret", start, err)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetSecret: ports.SecretRepository
+func (r *Repository) GetSecret(exchange, label string) (*domain.EncryptedSecret, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanSecret(r.db.QueryRow(ctx, `SELECT `+secretColumns+` FROM exchange_secrets WHERE exchange = $1 AND label = $2`, exchange, label))
+}
+
+// ListSecrets: ports.SecretRepository
+func (r *Repository) ListSecrets() ([]domain.EncryptedSecret, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx, `SELECT `+secretColumns+` FROM exchange_secrets ORDER BY exchange, label`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.EncryptedSecret
+	for rows.Next() {
+		s, err := scanSecret(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *s)
+	}
+	return out, rows.Err()
+}
+
+// DeleteSecret: ports.SecretRepository
+func (r *Repository) DeleteSecret(exchange, label string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM exchange_secrets WHERE exchange = $1 AND label = $2`, exchange, label)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSecretNotFound
+	}
+	return nil
+}
+
+func scanSecret(row pgx.Row) (*domain.EncryptedSecret, error) {
+	var s domain.EncryptedSecret
+	err := row.Scan(&s.Exchange, &s.Label, &s.KeyVersion, &s.APIKey, &s.APISecret, &s.APIKeyHint, &s.CreatedAt, &s.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrSecretNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
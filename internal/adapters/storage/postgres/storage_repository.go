@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// ApplyStoragePolicy: Ayarlardaki sıkıştırma ve interval bazlı saklama sürelerini veritabanına uygular.
+// Migration'lar politikaların "altyapısını" kurar, süreler ise her açılışta buradan senkronize edilir.
+func (r *Repository) ApplyStoragePolicy(policy domain.StoragePolicy) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// 1. Sıkıştırma politikası: önce eskisini kaldır, süre verilmişse yenisini ekle.
+	_, err := r.db.Exec(ctx, `SELECT remove_compression_policy('candles', if_exists => TRUE)`)
+	if err != nil {
+		return fmt.Errorf("sıkıştırma politikası kaldırılamadı: %w", err)
+	}
+	if policy.CompressAfter > 0 {
+		_, err = r.db.Exec(ctx,
+			`SELECT add_compression_policy('candles', compress_after => $1::interval)`,
+			pgInterval(policy.CompressAfter),
+		)
+		if err != nil {
+			return fmt.Errorf("sıkıştırma politikası eklenemedi: %w", err)
+		}
+	}
+
+	// 2. Saklama süreleri: tabloyu ayarlarla birebir aynı hale getir.
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM candle_retention_policies`); err != nil {
+		return fmt.Errorf("saklama politikaları temizlenemedi: %w", err)
+	}
+	for interval, keepFor := range policy.Retention {
+		if keepFor <= 0 {
+			continue
+		}
+		_, err := tx.Exec(ctx,
+			`INSERT INTO candle_retention_policies (interval, keep_for) VALUES ($1, $2::interval)`,
+			interval, pgInterval(keepFor),
+		)
+		if err != nil {
+			return fmt.Errorf("saklama politikası eklenemedi (%s): %w", interval, err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetCandleStorageStats: candles hypertable'ının chunk bazında boyutlarını ve sıkıştırma oranlarını döner.
+func (r *Repository) GetCandleStorageStats() (*domain.StorageStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+	SELECT c.chunk_name,
+	       c.range_start,
+	       c.range_end,
+	       c.is_compressed,
+	       COALESCE(d.total_bytes, 0),
+	       COALESCE(s.before_compression_total_bytes, 0),
+	       COALESCE(s.after_compression_total_bytes, 0)
+	FROM timescaledb_information.chunks c
+	LEFT JOIN chunks_detailed_size('candles') d
+	       ON d.chunk_schema = c.chunk_schema AND d.chunk_name = c.chunk_name
+	LEFT JOIN chunk_compression_stats('candles') s
+	       ON s.chunk_schema = c.chunk_schema AND s.chunk_name = c.chunk_name
+	WHERE c.hypertable_name = 'candles'
+	ORDER BY c.range_start
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("chunk istatistikleri okunamadı: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &domain.StorageStats{Hypertable: "candles", Chunks: []domain.ChunkStats{}}
+	var totalBefore, totalAfter int64
+	for rows.Next() {
+		var c domain.ChunkStats
+		err := rows.Scan(
+			&c.Name,
+			&c.RangeStart,
+			&c.RangeEnd,
+			&c.IsCompressed,
+			&c.TotalBytes,
+			&c.BeforeBytes,
+			&c.AfterBytes,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.IsCompressed && c.AfterBytes > 0 {
+			c.CompressionRatio = float64(c.BeforeBytes) / float64(c.AfterBytes)
+			totalBefore += c.BeforeBytes
+			totalAfter += c.AfterBytes
+			stats.CompressedChunks++
+		}
+		stats.TotalBytes += c.TotalBytes
+		stats.Chunks = append(stats.Chunks, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stats.ChunkCount = len(stats.Chunks)
+	if totalAfter > 0 {
+		stats.CompressionRatio = float64(totalBefore) / float64(totalAfter)
+	}
+	return stats, nil
+}
+
+// pgInterval: Go süresini Postgres INTERVAL'ine cast edilebilecek metne çevirir.
+func pgInterval(d time.Duration) string {
+	return fmt.Sprintf("%d seconds", int64(d.Seconds()))
+}
@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer: Global TracerProvider'dan alınır; tracing kurulmamışsa span'lar no-op'tur.
+var tracer = otel.Tracer("v2-trading-bot/adapters/storage")
+
+// endSpan: Hata varsa span'a işler ve span'ı kapatır.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
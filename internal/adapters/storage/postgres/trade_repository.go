@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"v2-trading-bot/internal/adapters/metrics"
+	"v2-trading-bot/internal/core/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// tradeColumns: trades tablosuna yazılan kolonlar. SaveTrades'teki satır sırasıyla aynı olmalı.
+var tradeColumns = []string{"time", "symbol", "agg_trade_id", "price", "quantity", "first_trade_id", "last_trade_id", "is_buyer_maker"}
+
+// SaveTrades: İşlemleri CopyFrom + staging tablosu ile toplu yazar.
+// Aynı işlem tekrar gelirse (yeniden bağlanma sonrası) yok sayılır.
+func (r *Repository) SaveTrades(ctx context.Context, trades []domain.Trade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := r.saveTrades(ctx, trades)
+	metrics.ObserveDBWrite("save_trades", start, err)
+	return err
+}
+
+func (r *Repository) saveTrades(ctx context.Context, trades []domain.Trade) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("trade transaction açılamadı: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `CREATE TEMP TABLE trades_staging (LIKE trades INCLUDING DEFAULTS) ON COMMIT DROP`)
+	if err != nil {
+		return fmt.Errorf("trade staging tablosu oluşturulamadı: %w", err)
+	}
+
+	rows := make([][]any, 0, len(trades))
+	for _, t := range trades {
+		rows = append(rows, []any{t.Time, t.Symbol, t.AggTradeID, t.Price, t.Quantity, t.FirstTradeID, t.LastTradeID, t.IsBuyerMaker})
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"trades_staging"}, tradeColumns, pgx.CopyFromRows(rows)); err != nil {
+		return fmt.Errorf("trade CopyFrom hatası: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO trades (time, symbol, agg_trade_id, price, quantity, first_trade_id, last_trade_id, is_buyer_maker)
+	SELECT time, symbol, agg_trade_id, price, quantity, first_trade_id, last_trade_id, is_buyer_maker FROM trades_staging
+	ON CONFLICT DO NOTHING;
+	`)
+	if err != nil {
+		return fmt.Errorf("trade staging aktarım hatası: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// TradeBatcher: İşlemleri biriktirip periyodik olarak SaveTrades ile yazar.
+// ports.TradeRepository'i implemente eder. Tick verisi kline'lardan çok daha sık geldiği için
+// tek tek INSERT burada hiç seçenek değil.
+type TradeBatcher struct {
+	repo          *Repository
+	size          int
+	maxPending    int
+	flushInterval time.Duration
+
+	mu  sync.Mutex
+	buf []domain.Trade
+
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+func NewTradeBatcher(repo *Repository, size int, flushInterval time.Duration) *TradeBatcher {
+	if size <= 0 {
+		size = 2000
+	}
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	return &TradeBatcher{
+		repo:          repo,
+		size:          size,
+		maxPending:    size * 50,
+		flushInterval: flushInterval,
+		flushCh:       make(chan struct{}, 1),
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// Start: Arka planda periyodik yazma döngüsünü başlatır.
+func (b *TradeBatcher) Start() {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := time.NewTicker(b.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-b.stopCh:
+				return
+			case <-ticker.C:
+			case <-b.flushCh:
+			}
+			if err := b.flush(); err != nil {
+				log.Warn("trade yazma hatası (tekrar denenecek)", "err", err)
+			}
+		}
+	}()
+}
+
+// SaveTrade: İşlemi tampona ekler.
+func (b *TradeBatcher) SaveTrade(trade domain.Trade) error {
+	b.mu.Lock()
+	if len(b.buf) >= b.maxPending {
+		b.mu.Unlock()
+		return fmt.Errorf("trade tamponu dolu (%d bekleyen işlem)", b.maxPending)
+	}
+	b.buf = append(b.buf, trade)
+	full := len(b.buf) >= b.size
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Close: Döngüyü durdurur ve kalan işlemleri yazar.
+func (b *TradeBatcher) Close() error {
+	close(b.stopCh)
+	b.wg.Wait()
+	return b.flush()
+}
+
+func (b *TradeBatcher) flush() error {
+	b.mu.Lock()
+	batch := b.buf
+	b.buf = nil
+	b.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := b.repo.SaveTrades(ctx, batch); err != nil {
+		b.mu.Lock()
+		b.buf = append(batch, b.buf...)
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const userColumns = `username, password_hash, role, accounts, api_key_hash IS NOT NULL, created_at`
+
+// CreateUser: ports.UserRepository
+func (r *Repository) CreateUser(u domain.User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	accounts := u.Accounts
+	if accounts == nil {
+		accounts = []string{}
+	}
+	_, err := r.db.Exec(ctx, `
+	INSERT INTO users (username, password_hash, role, accounts)
+	VALUES ($1, $2, $3, $4)
+	`, u.Username, u.PasswordHash, string(u.Role), accounts)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return domain.ErrUserExists
+	}
+	return err
+}
+
+// GetUser: ports.UserRepository
+func (r *Repository) GetUser(username string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanUser(r.db.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE username = $1`, username))
+}
+
+// GetUserByAPIKey: ports.UserRepository
+func (r *Repository) GetUserByAPIKey(keyHash string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanUser(r.db.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE api_key_hash = $1`, keyHash))
+}
+
+// ListUsers: ports.UserRepository. Kullanıcı adına göre sıralıdır.
+func (r *Repository) ListUsers() ([]domain.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx, `SELECT `+userColumns+` FROM users ORDER BY username`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *u)
+	}
+	return out, rows.Err()
+}
+
+// SetAPIKey: ports.UserRepository
+func (r *Repository) SetAPIKey(username, keyHash string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx, `UPDATE users SET api_key_hash = $1 WHERE username = $2`, keyHash, username)
+	if err != nil {
+		return fmt.Errorf("API anahtarı kaydedilemedi: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func scanUser(row pgx.Row) (*domain.User, error) {
+	var (
+		u    domain.User
+		role string
+	)
+	err := row.Scan(&u.Username, &u.PasswordHash, &role, &u.Accounts, &u.HasAPIKey, &u.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.Role = domain.Role(role)
+	return &u, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"time"
+	"v2-trading-bot/internal/adapters/metrics"
 	"v2-trading-bot/internal/core/domain"
 )
 
@@ -66,7 +67,9 @@ func (r *Repository) UpdateWallet(w domain.Wallet) error {
 	SET usdt_balance = $1, coin_balance = $2, updated_at = NOW() 
 	WHERE id = $3
 	`
+	start := time.Now()
 	_, err := r.db.Exec(ctx, query, w.USDTBalance, w.CoinBalance, w.ID)
+	metrics.ObserveDBWrite("update_wallet", start, err)
 	if err != nil {
 		return fmt.Errorf("cüzdan güncellenemedi: %w", err)
 	}
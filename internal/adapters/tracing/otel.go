@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Config: OTLP (HTTP/protobuf) dışa aktarım ayarları.
+type Config struct {
+	// Endpoint: Collector adresi (Örn: http://localhost:4318). Yol verilmezse /v1/traces eklenir.
+	Endpoint string
+	// ServiceName: Span'ların service.name niteliği.
+	ServiceName string
+	// SampleRatio: Örneklenecek trace oranı (1 = hepsi, 0.1 = %10).
+	SampleRatio float64
+}
+
+// Setup: Global TracerProvider'ı OTLP exporter ile kurar. Kurulmazsa otel.Tracer ile açılan
+// span'lar hiçbir şey yapmaz (no-op), yani tracing kapalıyken maliyet yoktur.
+// Dönen fonksiyon kapanışta çağrılmalıdır; tampondaki span'ları gönderir.
+func Setup(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("OTLP exporter oluşturulamadı: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,166 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"v2-trading-bot/internal/adapters/broker/stream"
+	"v2-trading-bot/internal/adapters/tracing"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+	"v2-trading-bot/internal/core/services"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// collector: /v1/traces'e gelen OTLP/HTTP (protobuf) isteklerini toplayan süreç içi stub.
+type collector struct {
+	mu    sync.Mutex
+	spans []*tracepb.Span
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/traces" {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req collectortrace.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+	c.mu.Unlock()
+
+	resp, _ := proto.Marshal(&collectortrace.ExportTraceServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(resp)
+}
+
+// fakeCandles: RSI(3)'ü sıfıra çeken, sürekli düşen geçmiş (DESC) döner; strateji BUY üretir.
+type fakeCandles struct{}
+
+func (fakeCandles) Save(domain.Candle) error        { return nil }
+func (fakeCandles) SavePartial(domain.Candle) error { return nil }
+func (fakeCandles) GetLatestCandles(exchange, symbol, interval string, limit int) ([]domain.Candle, error) {
+	candles := make([]domain.Candle, limit)
+	for i := range candles {
+		candles[i] = domain.Candle{Exchange: exchange, Symbol: symbol, Interval: interval, Close: 100 + float64(i)}
+	}
+	return candles, nil
+}
+
+type fakeWallet struct{}
+
+func (fakeWallet) GetWallet() (*domain.Wallet, error) {
+	return &domain.Wallet{ID: "demo", USDTBalance: 1000}, nil
+}
+func (fakeWallet) UpdateWallet(domain.Wallet) error { return nil }
+
+type fakeBus struct{ ports.EventBus }
+
+func (fakeBus) PublishCandle(domain.Candle) error       { return nil }
+func (fakeBus) PublishSignal(domain.TradeSignal) error  { return nil }
+func (fakeBus) PublishWallet(domain.WalletUpdate) error { return nil }
+
+func TestSetupExportsCandlePipelineSpans(t *testing.T) {
+	stub := &collector{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ctx := context.Background()
+	shutdown, err := tracing.Setup(ctx, tracing.Config{
+		Endpoint:    server.URL + "/v1/traces",
+		ServiceName: "trading-bot-test",
+		SampleRatio: 1,
+	})
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	service := services.NewTradingService(fakeCandles{}, fakeWallet{}, fakeBus{})
+	candle := domain.Candle{
+		Exchange:  domain.ExchangeBinance,
+		Symbol:    "BTCUSDT",
+		Interval:  "1m",
+		Close:     99,
+		OpenTime:  time.UnixMilli(1717999920000),
+		CloseTime: time.UnixMilli(1717999979999),
+		IsClosed:  true,
+	}
+	receiveCtx := stream.StartReceive(domain.ExchangeBinance, time.Now(), candle)
+	if err := service.ProcessIncomingCandle(receiveCtx, candle); err != nil {
+		t.Fatalf("ProcessIncomingCandle: %v", err)
+	}
+	stream.EndReceive(receiveCtx)
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	stub.mu.Lock()
+	spans := stub.spans
+	stub.mu.Unlock()
+
+	names := make(map[string]string, len(spans)) // spanID -> ad
+	for _, s := range spans {
+		names[hex.EncodeToString(s.SpanId)] = s.Name
+	}
+	// edges: "çocuk <- ebeveyn" çiftleri; aynı adlı span'lar (publish) birden fazla olabilir.
+	edges := make(map[string]bool)
+	var roots []string
+	for _, s := range spans {
+		if string(s.TraceId) != string(spans[0].TraceId) {
+			t.Errorf("%s span'ı farklı bir trace'te", s.Name)
+		}
+		if len(s.ParentSpanId) == 0 {
+			roots = append(roots, s.Name)
+			continue
+		}
+		edges[s.Name+" <- "+names[hex.EncodeToString(s.ParentSpanId)]] = true
+	}
+
+	if len(roots) != 1 || roots[0] != "binance receive" {
+		t.Errorf("tek kök span 'binance receive' olmalı, gelen: %v", roots)
+	}
+	for _, edge := range []string{
+		"parse <- binance receive",
+		"ProcessIncomingCandle <- binance receive",
+		"save <- ProcessIncomingCandle",
+		"publish <- ProcessIncomingCandle",
+		"history fetch <- ProcessIncomingCandle",
+		"indicators <- ProcessIncomingCandle",
+		"signal <- ProcessIncomingCandle",
+		"publish <- signal",
+		"execute <- signal",
+		"wallet load <- execute",
+		"wallet update <- execute",
+		"publish <- execute",
+	} {
+		if !edges[edge] {
+			t.Errorf("beklenen span ilişkisi yok: %s", edge)
+		}
+	}
+	if t.Failed() {
+		t.Logf("gelen span'lar: %v", edges)
+	}
+}
@@ -0,0 +1,111 @@
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Keyring: Sürümlü master anahtarlar. Yeni kayıtlar her zaman en yüksek sürümle şifrelenir;
+// eski sürümler sadece okuma (ve Rotate ile yeniden şifreleme) için tutulur.
+type Keyring struct {
+	keys    map[int]cipher.AEAD
+	current int
+}
+
+// ParseKeyring: "1:<base64>,2:<base64>" formatındaki listeyi okur. Her anahtar 32 bayt
+// (AES-256) olmalıdır. Üretmek için: openssl rand -base64 32
+// Satır sonları da ayırıcı kabul edilir; böylece aynı format dosyada satır satır yazılabilir.
+func ParseKeyring(s string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[int]cipher.AEAD)}
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' || r == '\r' })
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" || strings.HasPrefix(part, "#") {
+			continue
+		}
+		rawVersion, encoded, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, errors.New("geçersiz master anahtar (beklenen: sürüm:base64)")
+		}
+		version, err := strconv.Atoi(strings.TrimSpace(rawVersion))
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("geçersiz anahtar sürümü %q", rawVersion)
+		}
+		if _, dup := k.keys[version]; dup {
+			return nil, fmt.Errorf("anahtar sürümü %d iki kez tanımlı", version)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("anahtar %d base64 değil: %w", version, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("anahtar %d 32 bayt olmalı (şu an %d)", version, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[version] = aead
+		k.current = max(k.current, version)
+	}
+	if len(k.keys) == 0 {
+		return nil, errors.New("master anahtar tanımlı değil")
+	}
+	return k, nil
+}
+
+// LoadKeyring: Anahtarları önce dosyadan (Docker/K8s secret), yoksa env değerinden okur.
+func LoadKeyring(value, file string) (*Keyring, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("master anahtar dosyası okunamadı: %w", err)
+		}
+		value = string(data)
+	}
+	return ParseKeyring(value)
+}
+
+// CurrentVersion: Yeni kayıtları şifreleyen anahtarın sürümü.
+func (k *Keyring) CurrentVersion() int {
+	return k.current
+}
+
+// encrypt: Güncel anahtarla şifreler. aad (Örn: "binance/main") şifreli metne bağlanır;
+// bir kaydın şifreli değeri başka bir kayda kopyalanırsa çözülemez.
+func (k *Keyring) encrypt(plaintext, aad []byte) (version int, ciphertext []byte, err error) {
+	aead := k.keys[k.current]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return 0, nil, err
+	}
+	return k.current, aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// decrypt: Verilen sürümün anahtarıyla çözer.
+func (k *Keyring) decrypt(version int, ciphertext, aad []byte) ([]byte, error) {
+	aead, ok := k.keys[version]
+	if !ok {
+		return nil, fmt.Errorf("master anahtar sürümü %d yüklü değil", version)
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("şifreli veri bozuk")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, aad)
+	if err != nil {
+		return nil, errors.New("şifre çözülemedi (yanlış anahtar veya bozuk veri)")
+	}
+	return plaintext, nil
+}
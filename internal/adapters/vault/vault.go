@@ -0,0 +1,179 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+	"v2-trading-bot/internal/logger"
+)
+
+// Vault: Borsa API anahtarlarını Postgres'te AES-256-GCM ile şifreli saklar.
+// Sırlar API'ye asla açık dönmez; sadece Credentials (ports.CredentialProvider) çözer.
+type Vault struct {
+	repo    ports.SecretRepository
+	keyring *Keyring
+	log     *slog.Logger
+}
+
+func NewVault(repo ports.SecretRepository, keyring *Keyring) *Vault {
+	return &Vault{repo: repo, keyring: keyring, log: logger.For("vault")}
+}
+
+// Put: Anahtarı şifreleyip kaydeder (varsa üzerine yazar). Dönen bilgi maskelenmiştir.
+func (v *Vault) Put(creds domain.ExchangeCredentials) (*domain.SecretInfo, error) {
+	if creds.Exchange == "" || creds.Label == "" {
+		return nil, errors.New("exchange ve label boş olamaz")
+	}
+	if creds.APIKey == "" || creds.APISecret == "" {
+		return nil, errors.New("api_key ve api_secret boş olamaz")
+	}
+
+	secret, err := v.seal(creds)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.repo.SaveSecret(*secret); err != nil {
+		return nil, err
+	}
+	v.log.Info("API anahtarı kaydedildi", "credentials", creds, "key_version", secret.KeyVersion)
+	return info(*secret), nil
+}
+
+// Credentials: ports.CredentialProvider. Şifreli anahtarı çözen tek yöntem (henüz çağıran yok).
+func (v *Vault) Credentials(exchange, label string) (domain.ExchangeCredentials, error) {
+	secret, err := v.repo.GetSecret(exchange, label)
+	if err != nil {
+		return domain.ExchangeCredentials{}, err
+	}
+	return v.open(*secret)
+}
+
+// List: Kayıtlı anahtarlar (maskelenmiş).
+func (v *Vault) List() ([]domain.SecretInfo, error) {
+	secrets, err := v.repo.ListSecrets()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.SecretInfo, 0, len(secrets))
+	for _, s := range secrets {
+		out = append(out, *info(s))
+	}
+	return out, nil
+}
+
+// Delete: Anahtarı kalıcı olarak siler.
+func (v *Vault) Delete(exchange, label string) error {
+	if err := v.repo.DeleteSecret(exchange, label); err != nil {
+		return err
+	}
+	v.log.Info("API anahtarı silindi", "exchange", exchange, "label", label)
+	return nil
+}
+
+// Rotate: Güncel master anahtarla şifrelenmemiş kayıtları çözüp yeniden şifreler.
+// Yeni master anahtar eklendikten sonra çağrılır; tüm kayıtlar taşındıktan sonra eski
+// anahtar ayarlardan çıkarılabilir. Yeniden şifrelenen kayıt sayısını döner.
+func (v *Vault) Rotate() (int, error) {
+	secrets, err := v.repo.ListSecrets()
+	if err != nil {
+		return 0, err
+	}
+	rotated := 0
+	for _, s := range secrets {
+		if s.KeyVersion == v.keyring.CurrentVersion() {
+			continue
+		}
+		creds, err := v.open(s)
+		if err != nil {
+			return rotated, err
+		}
+		sealed, err := v.seal(creds)
+		if err != nil {
+			return rotated, err
+		}
+		// Okuma ile yazma arasında Put/Delete olduysa o kayda dokunulmaz (yeni anahtar zaten
+		// güncel sürümle şifrelidir); körü körüne yazmak eski API anahtarını geri getirirdi.
+		replaced, err := v.repo.ReplaceSecret(*sealed, s)
+		if err != nil {
+			return rotated, err
+		}
+		if !replaced {
+			v.log.Info("API anahtarı rotation sırasında değişti, atlandı", "exchange", s.Exchange, "label", s.Label)
+			continue
+		}
+		rotated++
+		v.log.Info("API anahtarı yeniden şifrelendi", "exchange", s.Exchange, "label", s.Label,
+			"from_version", s.KeyVersion, "to_version", sealed.KeyVersion)
+	}
+	return rotated, nil
+}
+
+// Stale: Güncel olmayan master anahtarla şifrelenmiş kayıt sayısı (açılışta uyarı için).
+func (v *Vault) Stale() (int, error) {
+	secrets, err := v.repo.ListSecrets()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, s := range secrets {
+		if s.KeyVersion != v.keyring.CurrentVersion() {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (v *Vault) seal(creds domain.ExchangeCredentials) (*domain.EncryptedSecret, error) {
+	aad := []byte(creds.Exchange + "/" + creds.Label)
+	version, apiKey, err := v.keyring.encrypt([]byte(creds.APIKey), aad)
+	if err != nil {
+		return nil, err
+	}
+	_, apiSecret, err := v.keyring.encrypt([]byte(creds.APISecret), aad)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &domain.EncryptedSecret{
+		Exchange:   creds.Exchange,
+		Label:      creds.Label,
+		KeyVersion: version,
+		APIKey:     apiKey,
+		APISecret:  apiSecret,
+		APIKeyHint: domain.MaskSecret(creds.APIKey),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+func (v *Vault) open(s domain.EncryptedSecret) (domain.ExchangeCredentials, error) {
+	aad := []byte(s.Exchange + "/" + s.Label)
+	apiKey, err := v.keyring.decrypt(s.KeyVersion, s.APIKey, aad)
+	if err != nil {
+		return domain.ExchangeCredentials{}, fmt.Errorf("%s/%s: %w", s.Exchange, s.Label, err)
+	}
+	apiSecret, err := v.keyring.decrypt(s.KeyVersion, s.APISecret, aad)
+	if err != nil {
+		return domain.ExchangeCredentials{}, fmt.Errorf("%s/%s: %w", s.Exchange, s.Label, err)
+	}
+	return domain.ExchangeCredentials{
+		Exchange:  s.Exchange,
+		Label:     s.Label,
+		APIKey:    string(apiKey),
+		APISecret: string(apiSecret),
+	}, nil
+}
+
+func info(s domain.EncryptedSecret) *domain.SecretInfo {
+	return &domain.SecretInfo{
+		Exchange:     s.Exchange,
+		Label:        s.Label,
+		APIKeyMasked: s.APIKeyHint,
+		KeyVersion:   s.KeyVersion,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
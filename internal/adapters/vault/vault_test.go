@@ -0,0 +1,242 @@
+package vault
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// memSecrets: Kayıtları bellekte tutan ports.SecretRepository. beforeReplace verilirse
+// ReplaceSecret koşulu kontrol edilmeden önce çağrılır (araya giren Put'u taklit etmek için).
+type memSecrets struct {
+	mu            sync.Mutex
+	secrets       map[string]domain.EncryptedSecret
+	beforeReplace func(secret domain.EncryptedSecret)
+}
+
+func newMemSecrets() *memSecrets {
+	return &memSecrets{secrets: make(map[string]domain.EncryptedSecret)}
+}
+
+func secretKey(exchange, label string) string { return exchange + "/" + label }
+
+func (m *memSecrets) SaveSecret(secret domain.EncryptedSecret) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secrets[secretKey(secret.Exchange, secret.Label)] = secret
+	return nil
+}
+
+func (m *memSecrets) ReplaceSecret(secret, previous domain.EncryptedSecret) (bool, error) {
+	if m.beforeReplace != nil {
+		m.beforeReplace(secret)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := secretKey(secret.Exchange, secret.Label)
+	current, ok := m.secrets[key]
+	if !ok || current.KeyVersion != previous.KeyVersion || !current.UpdatedAt.Equal(previous.UpdatedAt) {
+		return false, nil
+	}
+	m.secrets[key] = secret
+	return true, nil
+}
+
+func (m *memSecrets) GetSecret(exchange, label string) (*domain.EncryptedSecret, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	secret, ok := m.secrets[secretKey(exchange, label)]
+	if !ok {
+		return nil, domain.ErrSecretNotFound
+	}
+	return &secret, nil
+}
+
+func (m *memSecrets) ListSecrets() ([]domain.EncryptedSecret, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]domain.EncryptedSecret, 0, len(m.secrets))
+	for _, secret := range m.secrets {
+		out = append(out, secret)
+	}
+	slices.SortFunc(out, func(a, b domain.EncryptedSecret) int {
+		return strings.Compare(secretKey(a.Exchange, a.Label), secretKey(b.Exchange, b.Label))
+	})
+	return out, nil
+}
+
+func (m *memSecrets) DeleteSecret(exchange, label string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.secrets, secretKey(exchange, label))
+	return nil
+}
+
+// testKey: Sürüme göre farklı, sabit 32 baytlık anahtar.
+func testKey(version int) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(fmt.Sprint(version%10), 32)))
+}
+
+func testKeyring(t *testing.T, versions ...int) *Keyring {
+	t.Helper()
+	var parts []string
+	for _, v := range versions {
+		parts = append(parts, fmt.Sprintf("%d:%s", v, testKey(v)))
+	}
+	k, err := ParseKeyring(strings.Join(parts, ","))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func testCreds(exchange, label string) domain.ExchangeCredentials {
+	return domain.ExchangeCredentials{Exchange: exchange, Label: label, APIKey: "key-" + label, APISecret: "secret-" + label}
+}
+
+func TestParseKeyring(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		current int
+		err     string
+	}{
+		{"tek anahtar", "1:" + testKey(1), 1, ""},
+		{"en yüksek sürüm güncel", "2:" + testKey(2) + ",1:" + testKey(1), 2, ""},
+		{"satır satır ve yorum", "# eski\n1:" + testKey(1) + "\n\n3:" + testKey(3) + "\n", 3, ""},
+		{"boş", " , ", 0, "tanımlı değil"},
+		{"sürüm yok", testKey(1), 0, "sürüm:base64"},
+		{"geçersiz sürüm", "0:" + testKey(1), 0, "geçersiz anahtar sürümü"},
+		{"tekrar eden sürüm", "1:" + testKey(1) + ",1:" + testKey(2), 0, "iki kez"},
+		{"base64 değil", "1:***", 0, "base64 değil"},
+		{"kısa anahtar", "1:" + base64.StdEncoding.EncodeToString(make([]byte, 16)), 0, "32 bayt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := ParseKeyring(tt.input)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("%q içeren hata bekleniyordu: %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if k.CurrentVersion() != tt.current {
+				t.Fatalf("güncel sürüm %d, beklenen %d", k.CurrentVersion(), tt.current)
+			}
+		})
+	}
+}
+
+func TestVaultSealOpenRoundTrip(t *testing.T) {
+	repo := newMemSecrets()
+	v := NewVault(repo, testKeyring(t, 1))
+
+	want := testCreds(domain.ExchangeBinance, "main")
+	info, err := v.Put(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.KeyVersion != 1 || strings.Contains(info.APIKeyMasked, want.APIKey) {
+		t.Fatalf("maskelenmiş bilgi: %+v", info)
+	}
+	stored, _ := repo.GetSecret(want.Exchange, want.Label)
+	if strings.Contains(string(stored.APIKey), want.APIKey) || strings.Contains(string(stored.APISecret), want.APISecret) {
+		t.Fatal("veritabanına düz metin yazılmamalı")
+	}
+
+	got, err := v.Credentials(want.Exchange, want.Label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("çözülen %+v, beklenen %+v", got, want)
+	}
+}
+
+func TestVaultCiphertextBoundToRecord(t *testing.T) {
+	repo := newMemSecrets()
+	v := NewVault(repo, testKeyring(t, 1))
+	if _, err := v.Put(testCreds(domain.ExchangeBinance, "main")); err != nil {
+		t.Fatal(err)
+	}
+	original, _ := repo.GetSecret(domain.ExchangeBinance, "main")
+
+	for _, target := range []struct{ exchange, label string }{
+		{domain.ExchangeBybit, "main"},     // başka borsa
+		{domain.ExchangeBinance, "backup"}, // başka etiket
+	} {
+		moved := *original
+		moved.Exchange, moved.Label = target.exchange, target.label
+		repo.SaveSecret(moved)
+		if _, err := v.Credentials(target.exchange, target.label); err == nil {
+			t.Fatalf("%s/%s kaydına taşınan şifreli değer çözülmemeli", target.exchange, target.label)
+		}
+	}
+}
+
+func TestVaultUnknownKeyVersion(t *testing.T) {
+	repo := newMemSecrets()
+	if _, err := NewVault(repo, testKeyring(t, 1, 2)).Put(testCreds(domain.ExchangeBinance, "main")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sürüm 2 ayarlardan çıkarıldı: kayıt çözülemez, açık hata döner.
+	_, err := NewVault(repo, testKeyring(t, 1)).Credentials(domain.ExchangeBinance, "main")
+	if err == nil || !strings.Contains(err.Error(), "sürümü 2 yüklü değil") {
+		t.Fatalf("bilinmeyen sürüm hatası bekleniyordu: %v", err)
+	}
+}
+
+func TestVaultRotate(t *testing.T) {
+	repo := newMemSecrets()
+	old := NewVault(repo, testKeyring(t, 1))
+	for _, label := range []string{"a", "b", "c"} {
+		if _, err := old.Put(testCreds(domain.ExchangeBinance, label)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v := NewVault(repo, testKeyring(t, 1, 2))
+	if n, _ := v.Stale(); n != 3 {
+		t.Fatalf("3 eski kayıt bekleniyordu, %d", n)
+	}
+
+	// "b" yeniden şifrelenirken kullanıcı yeni anahtar kaydeder: rotation onu ezmemeli.
+	fresh := domain.ExchangeCredentials{Exchange: domain.ExchangeBinance, Label: "b", APIKey: "new-key", APISecret: "new-secret"}
+	repo.beforeReplace = func(secret domain.EncryptedSecret) {
+		if secret.Label == "b" {
+			repo.beforeReplace = nil
+			if _, err := v.Put(fresh); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+	rotated, err := v.Rotate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated != 2 {
+		t.Fatalf("araya yazılan kayıt atlanmalı, %d kayıt yeniden şifrelendi", rotated)
+	}
+	if n, _ := v.Stale(); n != 0 {
+		t.Fatalf("rotation sonrası eski kayıt kalmamalı, %d", n)
+	}
+	if got, _ := v.Credentials(domain.ExchangeBinance, "b"); got != fresh {
+		t.Fatalf("araya yazılan anahtar korunmalı: %+v", got)
+	}
+
+	// Eski anahtar çıkarıldıktan sonra tüm kayıtlar okunabilir.
+	current := NewVault(repo, testKeyring(t, 2))
+	for _, label := range []string{"a", "c"} {
+		if got, err := current.Credentials(domain.ExchangeBinance, label); err != nil || got != testCreds(domain.ExchangeBinance, label) {
+			t.Fatalf("%s: %+v %v", label, got, err)
+		}
+	}
+}
@@ -3,75 +3,370 @@ package websocket
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
 
+	"v2-trading-bot/internal/adapters/auth"
+	"v2-trading-bot/internal/adapters/metrics"
 	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+	"v2-trading-bot/internal/logger"
 
 	"github.com/centrifugal/centrifuge"
 )
 
+// SocketConfig: WebSocket bağlantılarının kimlik doğrulama ve geçmiş ayarları.
+type SocketConfig struct {
+	// Tokens: Bağlantı tokenlarını doğrular. nil ise sadece anonim bağlantı mümkündür.
+	Tokens *auth.TokenManager
+	// AllowAnonymous: Tokensız istemciler herkese açık kanalları okuyabilir (cüzdan kanallarını göremez).
+	AllowAnonymous bool
+	// History: Namespace -> geçmiş politikası. nil ise DefaultHistory kullanılır.
+	History map[string]HistoryPolicy
+	// Catalog: Kanal adlarındaki sembol, interval ve stratejilerin bilinen değerleri.
+	Catalog ChannelCatalog
+	// Redis: Opsiyonel. Verilmezse broker ve presence süreç içi bellekte tutulur (tek süreç).
+	Redis *RedisConfig
+}
+
 type SocketService struct {
 	Node *centrifuge.Node
+
+	cfg SocketConfig
+	log *slog.Logger
+
+	// shards: Redis bağlantıları (Redis kapalıysa boş). Kapanışta kapatılır.
+	shards []*centrifuge.RedisShard
+
+	// wallets: Opsiyonel. Varsa "wallet:<account>" aboneliğinde cüzdanın son hali de gönderilir.
+	wallets ports.WalletRepository
+	// strategy, candles: Opsiyonel. RPC yöntemlerinin arka ucu (bkz. rpc.go).
+	strategy ports.StrategyController
+	candles  ports.CandleRepository
 }
 
-func NewSocketService() *SocketService {
-	cfg := centrifuge.Config{}
+func NewSocketService(socketCfg SocketConfig) *SocketService {
+	log := logger.For("centrifuge")
+	cfg := centrifuge.Config{
+		LogLevel:   centrifuge.LogLevelInfo,
+		LogHandler: logHandler(log),
+	}
 
 	node, err := centrifuge.New(cfg)
 	if err != nil {
-		log.Fatalf("Centrifuge motoru çalışmadı: %v", err)
-	}
-
-	// 👇 KRİTİK DÜZELTME BURADA 👇
-	// Gelen kullanıcıya bir ID vermezsek sistem "Bad Request" verir!
-	node.OnConnecting(func(ctx context.Context, e centrifuge.ConnectEvent) (centrifuge.ConnectReply, error) {
-		return centrifuge.ConnectReply{
-			// Herkese "misafir" veya rastgele bir ID veriyoruz.
-			Credentials: &centrifuge.Credentials{
-				UserID: "misafir_kullanici",
-			},
-		}, nil
-	})
+		log.Error("Centrifuge motoru çalışmadı", "err", err)
+		os.Exit(1)
+	}
+	if socketCfg.History == nil {
+		socketCfg.History = DefaultHistory
+	}
+	s := &SocketService{Node: node, cfg: socketCfg, log: log}
+
+	if socketCfg.Redis != nil {
+		s.shards, err = setupRedis(node, *socketCfg.Redis)
+		if err != nil {
+			log.Error("Redis broker kurulamadı", "err", err)
+			os.Exit(1)
+		}
+		log.Info("Redis broker kullanılıyor", "addresses", socketCfg.Redis.Addresses, "prefix", socketCfg.Redis.Prefix)
+	}
+
+	// Admin paneli: tüm node'lardaki istemcileri listeleme ve koparma (clients.go).
+	node.OnSurvey(s.onSurvey)
+
+	// Token "connect" komutuyla (centrifuge-js: new Centrifuge(url, { token })) veya HTTP
+	// başlığıyla (Authorization / X-API-Key, bkz. handler.MountRealtime) gelir.
+	node.OnConnecting(s.onConnecting)
 
 	node.OnConnect(func(client *centrifuge.Client) {
 		client.OnSubscribe(func(e centrifuge.SubscribeEvent, cb centrifuge.SubscribeCallback) {
-			cb(centrifuge.SubscribeReply{}, nil)
+			claims := sessionClaims(client)
+			if err := s.authorizeSubscribe(claims, e.Channel); err != nil {
+				log.Info("abonelik reddedildi", "user", client.UserID(), "channel", e.Channel, "err", err)
+				cb(centrifuge.SubscribeReply{}, err)
+				return
+			}
+			cb(s.subscribeReply(e.Channel), nil)
+		})
+		// Kanalı kimlerin dinlediğini sadece o kanala abone olan görebilir (cüzdan kanalı kişiye özel).
+		client.OnPresence(func(e centrifuge.PresenceEvent, cb centrifuge.PresenceCallback) {
+			if !presence(e.Channel) || !client.IsSubscribed(e.Channel) {
+				cb(centrifuge.PresenceReply{}, centrifuge.ErrorPermissionDenied)
+				return
+			}
+			cb(centrifuge.PresenceReply{}, nil)
+		})
+		client.OnPresenceStats(func(e centrifuge.PresenceStatsEvent, cb centrifuge.PresenceStatsCallback) {
+			if !presence(e.Channel) || !client.IsSubscribed(e.Channel) {
+				cb(centrifuge.PresenceStatsReply{}, centrifuge.ErrorPermissionDenied)
+				return
+			}
+			cb(centrifuge.PresenceStatsReply{}, nil)
 		})
+		// Tüm kanallar sunucuya ait; istemciler yayın yapamaz.
 		client.OnPublish(func(e centrifuge.PublishEvent, cb centrifuge.PublishCallback) {
-			cb(centrifuge.PublishReply{}, nil)
+			log.Warn("istemci yayını reddedildi", "user", client.UserID(), "channel", e.Channel)
+			cb(centrifuge.PublishReply{}, centrifuge.ErrorPermissionDenied)
+		})
+		// Komutlar (centrifuge-js: centrifuge.rpc(method, data)); yetki ve parametreler rpc.go'da.
+		client.OnRPC(func(e centrifuge.RPCEvent, cb centrifuge.RPCCallback) {
+			data, err := s.handleRPC(client.Context(), sessionClaims(client), e.Method, e.Data)
+			if err != nil {
+				log.Info("RPC reddedildi", "user", client.UserID(), "method", e.Method, "err", err)
+				cb(centrifuge.RPCReply{}, err)
+				return
+			}
+			cb(centrifuge.RPCReply{Data: data}, nil)
+		})
+		// Süresi dolan token'ı istemci yenisiyle değiştirir (centrifuge-js: getToken). Yeni token'daki
+		// rol ve hesaplar bağlantı kopmadan geçerli olur; yetkisi kalmayan kanallardan çıkarılır.
+		client.OnRefresh(func(e centrifuge.RefreshEvent, cb centrifuge.RefreshCallback) {
+			sess, ok := client.Context().Value(sessionKey{}).(*session)
+			claims, err := s.verify(e.Token)
+			if !ok || err != nil || claims.Subject != client.UserID() {
+				cb(centrifuge.RefreshReply{Expired: true}, nil)
+				return
+			}
+			sess.claims.Store(claims)
+			cb(centrifuge.RefreshReply{ExpireAt: claims.ExpiresAt.Unix()}, nil)
+
+			for _, channel := range client.Channels() {
+				if err := s.authorizeSubscribe(claims, channel); err != nil {
+					log.Info("yetkisi kalmayan kanaldan çıkarıldı", "user", client.UserID(), "channel", channel, "err", err)
+					client.Unsubscribe(channel)
+				}
+			}
 		})
 	})
 
 	if err := node.Run(); err != nil {
-		log.Fatalf("Centrifuge Run Hatası: %v", err)
+		log.Error("Centrifuge başlatılamadı", "err", err)
+		os.Exit(1)
 	}
 
-	return &SocketService{Node: node}
+	// Bağlı istemci sayısı /metrics'te görünsün
+	metrics.RegisterClientCount(node.Hub().NumClients)
+
+	return s
 }
 
-func (s *SocketService) PublishCandle(candle domain.Candle) error {
-	data, err := json.Marshal(candle)
+func (s *SocketService) onConnecting(ctx context.Context, e centrifuge.ConnectEvent) (centrifuge.ConnectReply, error) {
+	// Admin paneli bağlantı süresini gösterebilsin (bkz. clients.go).
+	ctx = context.WithValue(ctx, connectedAtKey{}, time.Now())
+
+	if e.Token == "" {
+		// Bağlantı isteğinin başlıkları REST ile aynı şekilde doğrulanmış olabilir.
+		if claims := auth.FromContext(ctx); claims != nil {
+			return connectReply(ctx, claims), nil
+		}
+		if !s.cfg.AllowAnonymous {
+			s.log.Info("tokensız bağlantı reddedildi", "transport", e.Transport.Name())
+			return centrifuge.ConnectReply{}, centrifuge.DisconnectInvalidToken
+		}
+		// Boş UserID anonim kullanıcı demektir; Credentials hiç verilmezse Centrifuge "Bad Request" döner.
+		return centrifuge.ConnectReply{Context: ctx, Credentials: &centrifuge.Credentials{}}, nil
+	}
+
+	claims, err := s.verify(e.Token)
 	if err != nil {
-		return err
+		s.log.Info("bağlantı reddedildi", "err", err)
+		return centrifuge.ConnectReply{}, centrifuge.DisconnectInvalidToken
 	}
-	_, err = s.Node.Publish("kline", data)
-	return err
+	return connectReply(ctx, claims), nil
 }
 
-func (s *SocketService) PublishSignal(signal domain.TradeSignal) error {
-	data, err := json.Marshal(signal)
+// sessionKey: Bağlantının güncel kullanıcısını tutan *session'ın context anahtarı.
+type sessionKey struct{}
+
+// session: Token yenilendiğinde (rol veya hesaplar değişmiş olabilir) bağlantının kullanıcısı
+// yerinde güncellenir; abonelik, RPC ve admin listesi hep sessionClaims ile okur.
+type session struct {
+	claims atomic.Pointer[auth.Claims]
+}
+
+// sessionClaims: İstemcinin güncel kullanıcısı (anonimse nil).
+func sessionClaims(client *centrifuge.Client) *auth.Claims {
+	if sess, ok := client.Context().Value(sessionKey{}).(*session); ok {
+		return sess.claims.Load()
+	}
+	return auth.FromContext(client.Context())
+}
+
+// connectReply: Kullanıcıyı bağlantıya bağlar. Süresi olan tokenlar istemci tarafından yenilenir;
+// API anahtarlarının süresi yoktur.
+func connectReply(ctx context.Context, claims *auth.Claims) centrifuge.ConnectReply {
+	sess := &session{}
+	sess.claims.Store(claims)
+	reply := centrifuge.ConnectReply{
+		Context:     context.WithValue(auth.NewContext(ctx, claims), sessionKey{}, sess),
+		Credentials: &centrifuge.Credentials{UserID: claims.Subject},
+	}
+	if claims.ExpiresAt != nil {
+		reply.Credentials.ExpireAt = claims.ExpiresAt.Unix()
+		reply.ClientSideRefresh = true
+	}
+	return reply
+}
+
+// SetWalletSource: Cüzdan kanalına abone olan istemciye ilk anda gönderilecek bakiyenin
+// okunacağı yeri ayarlar. Böylece istemci ilk işlemi beklemeden güncel bakiyeyi görür.
+func (s *SocketService) SetWalletSource(wallets ports.WalletRepository) {
+	s.wallets = wallets
+}
+
+// subscribeReply: Geçmişi olan kanallarda recovery'yi, ana kanallarda presence ve join/leave'i
+// açar; cüzdan kanalına anlık görüntü ekler.
+func (s *SocketService) subscribeReply(channel string) centrifuge.SubscribeReply {
+	var reply centrifuge.SubscribeReply
+	if _, ok := s.history(channel); ok {
+		reply.Options.EnableRecovery = true
+	}
+	if presence(channel) {
+		reply.Options.EmitPresence = true
+		reply.Options.EmitJoinLeave = true
+		reply.Options.PushJoinLeave = true
+	}
+	if name, params := parseChannel(channel); name == NamespaceWallet && s.wallets != nil {
+		account := params[0]
+		// Snapshot alınamazsa abonelik yine de olur; istemci sonraki güncellemeyi bekler.
+		snapshot, err := s.walletSnapshot(account)
+		if err != nil {
+			s.log.Warn("cüzdan anlık görüntüsü alınamadı", "account", account, "err", err)
+		} else {
+			reply.Options.Data = snapshot
+		}
+	}
+	return reply
+}
+
+// walletSnapshot: Cüzdanın son halini "wallet:<account>" yayınlarıyla aynı formatta döner.
+func (s *SocketService) walletSnapshot(account string) ([]byte, error) {
+	wallet, err := s.wallets.GetWallet()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if wallet.ID != account {
+		return nil, fmt.Errorf("cüzdan %q yok", account)
+	}
+	return json.Marshal(domain.WalletUpdate{Account: wallet.ID, USDT: wallet.USDTBalance, BTC: wallet.CoinBalance})
+}
+
+func (s *SocketService) verify(token string) (*auth.Claims, error) {
+	if s.cfg.Tokens == nil {
+		return nil, errors.New("token doğrulama ayarlanmamış")
+	}
+	return s.cfg.Tokens.Verify(token)
+}
+
+// logHandler: Centrifuge'ün kendi loglarını slog'a aktarır.
+func logHandler(log *slog.Logger) centrifuge.LogHandler {
+	return func(e centrifuge.LogEntry) {
+		level := slog.LevelInfo
+		switch {
+		case e.Level >= centrifuge.LogLevelError:
+			level = slog.LevelError
+		case e.Level == centrifuge.LogLevelWarn:
+			level = slog.LevelWarn
+		case e.Level <= centrifuge.LogLevelDebug:
+			level = slog.LevelDebug
+		}
+		attrs := make([]any, 0, 2*len(e.Fields)+2)
+		for k, v := range e.Fields {
+			attrs = append(attrs, k, v)
+		}
+		if e.Error != nil {
+			attrs = append(attrs, "err", e.Error)
+		}
+		log.Log(context.Background(), level, e.Message, attrs...)
+	}
+}
+
+// publish: Veriyi JSON'a çevirip kanala yayınlar. Başarısız yayınlar metriklere yazılır.
+func (s *SocketService) publish(channel string, v any) error {
+	var opts []centrifuge.PublishOption
+	if policy, ok := s.history(channel); ok {
+		opts = append(opts, centrifuge.WithHistory(policy.Size, policy.TTL))
+	}
+
+	data, err := json.Marshal(v)
+	if err == nil {
+		_, err = s.Node.Publish(channel, data, opts...)
+	}
+	if err != nil {
+		// Etiket namespace'tir; sembol/interval başına ayrı seri açılmasın.
+		name, _ := parseChannel(channel)
+		metrics.PublishFailures.WithLabelValues(name).Inc()
 	}
-	_, err = s.Node.Publish("signals", data)
 	return err
 }
 
+// PublishCandle: Kapanmış mumu "kline:<symbol>:<interval>" kanalına yayınlar.
+// Aynı sembolün farklı borsalardan gelen mumları aynı kanala düşer (Candle.Exchange ile ayrılır).
+func (s *SocketService) PublishCandle(candle domain.Candle) error {
+	return s.publish(channelName(NamespaceKline, candle.Symbol, candle.Interval), candle)
+}
+
+// PublishPartialCandle: Henüz kapanmamış mumu ayrı bir kanala ("kline_live:<symbol>:<interval>") yayınlar.
+// Böylece "kline" kanallarını dinleyenler sadece kesinleşmiş mumları görür.
+func (s *SocketService) PublishPartialCandle(candle domain.Candle) error {
+	return s.publish(channelName(NamespaceKlineLive, candle.Symbol, candle.Interval), candle)
+}
+
+// PublishSignal: Sinyali üreten stratejinin kanalına ("signals:<strategy>") yayınlar.
+func (s *SocketService) PublishSignal(signal domain.TradeSignal) error {
+	return s.publish(channelName(NamespaceSignals, signal.Strategy), signal)
+}
+
+// PublishWallet: Cüzdan güncellemesini sadece o hesabın kanalına ("wallet:<account>") yayınlar.
 func (s *SocketService) PublishWallet(update domain.WalletUpdate) error {
-	data, err := json.Marshal(update)
+	return s.publish(channelName(NamespaceWallet, update.Account), update)
+}
+
+// PublishBookTicker: Emir defterinin tepesini "ticker:<symbol>" kanalına yayınlar.
+func (s *SocketService) PublishBookTicker(ticker domain.BookTicker) error {
+	return s.publish(channelName(NamespaceTicker, ticker.Symbol), ticker)
+}
+
+// PublishIndex: Borsalar arası birleşik fiyatı "index:<symbol>" kanalına yayınlar.
+func (s *SocketService) PublishIndex(index domain.IndexPrice) error {
+	return s.publish(channelName(NamespaceIndex, index.Candle.Symbol), index)
+}
+
+// PublishArbitrage: Borsalar arası arbitraj fırsatını "arbitrage:<symbol>" kanalına yayınlar.
+func (s *SocketService) PublishArbitrage(opportunity domain.ArbitrageOpportunity) error {
+	return s.publish(channelName(NamespaceArbitrage, opportunity.Symbol), opportunity)
+}
+
+// PublishFeedAlert: Bayat veri akışı uyarılarını "alerts" kanalına yayınlar.
+func (s *SocketService) PublishFeedAlert(alert domain.FeedAlert) error {
+	return s.publish(NamespaceAlerts, alert)
+}
+
+// CheckHealth: ports.HealthCheck. Centrifuge node'unun çalıştığını ve broker'a ulaşabildiğini kontrol eder.
+func (s *SocketService) CheckHealth(ctx context.Context) error {
+	info, err := s.Node.Info()
 	if err != nil {
 		return err
 	}
-	_, err = s.Node.Publish("wallet", data)
+	if len(info.Nodes) == 0 {
+		return fmt.Errorf("centrifuge node kaydı yok")
+	}
+	if len(s.shards) > 0 {
+		// Geçmiş sorgusu broker'a (Redis) gider; Redis'e ulaşılamıyorsa hata döner.
+		if _, err := s.Node.History(NamespaceAlerts, centrifuge.WithLimit(0)); err != nil {
+			return fmt.Errorf("redis broker: %w", err)
+		}
+	}
+	return nil
+}
+
+// Shutdown: İstemcileri bağlantı kapanışı sebebiyle koparır ve Redis bağlantılarını kapatır.
+func (s *SocketService) Shutdown(ctx context.Context) error {
+	err := s.Node.Shutdown(ctx)
+	closeShards(s.shards)
 	return err
 }
@@ -0,0 +1,201 @@
+package websocket
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"v2-trading-bot/internal/adapters/auth"
+	"v2-trading-bot/internal/core/domain"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// Kanal adları "<namespace>:<parametre>:<parametre>" biçimindedir:
+//
+//	kline:BTCUSDT:1m       kapanmış mumlar
+//	kline_live:BTCUSDT:1m  kapanmamış mumlar (STREAM_PARTIAL_KLINES)
+//	signals:rsi            stratejinin sinyalleri
+//	ticker:BTCUSDT         emir defterinin tepesi (best bid/ask)
+//	index:BTCUSDT          borsalar arası birleşik fiyat
+//	arbitrage:BTCUSDT      arbitraj fırsatları
+//	alerts                 bayat veri akışı uyarıları
+//	wallet:demo            paper trading cüzdanı (kişiye özel)
+//
+// Joker (wildcard) abonelik yoktur; geçerli kanallar GET /api/v1/channels ile listelenir.
+const (
+	NamespaceKline     = "kline"
+	NamespaceKlineLive = "kline_live"
+	NamespaceSignals   = "signals"
+	NamespaceTicker    = "ticker"
+	NamespaceIndex     = "index"
+	NamespaceArbitrage = "arbitrage"
+	NamespaceAlerts    = "alerts"
+	NamespaceWallet    = "wallet"
+)
+
+// Kanal parametreleri. account dışındakiler ChannelCatalog'daki bilinen değerlerle doğrulanır.
+const (
+	paramSymbol   = "symbol"
+	paramInterval = "interval"
+	paramStrategy = "strategy"
+	paramAccount  = "account"
+)
+
+// namespace: Bir kanal ailesinin tanımı.
+type namespace struct {
+	params      []string
+	description string
+	// private: Parametresi hesap olan kanallar sadece o hesabı görebilen kullanıcıya açıktır.
+	private bool
+	// presence: Aboneler presence'a yazılır, join/leave olayları kanaldakilere gönderilir.
+	presence bool
+}
+
+var namespaces = map[string]namespace{
+	NamespaceKline:     {params: []string{paramSymbol, paramInterval}, description: "Kapanmış mumlar", presence: true},
+	NamespaceKlineLive: {params: []string{paramSymbol, paramInterval}, description: "Henüz kapanmamış mumlar"},
+	NamespaceSignals:   {params: []string{paramStrategy}, description: "Strateji sinyalleri", presence: true},
+	NamespaceTicker:    {params: []string{paramSymbol}, description: "Emir defterinin tepesi (best bid/ask)"},
+	NamespaceIndex:     {params: []string{paramSymbol}, description: "Borsalar arası birleşik fiyat"},
+	NamespaceArbitrage: {params: []string{paramSymbol}, description: "Arbitraj fırsatları"},
+	NamespaceAlerts:    {description: "Bayat veri akışı uyarıları"},
+	NamespaceWallet:    {params: []string{paramAccount}, description: "Paper trading cüzdanı", private: true, presence: true},
+}
+
+// HistoryPolicy: Bir namespace'in yayın geçmişi. Geçmişi olan kanallarda recovery açıktır:
+// kopup yeniden bağlanan istemci son gördüğü offset'ten itibaren kaçırdığı yayınları alır.
+type HistoryPolicy struct {
+	Size int           // Kanal başına tutulan en fazla yayın sayısı
+	TTL  time.Duration // Yayının geçmişte kalma süresi
+}
+
+// DefaultHistory: Ayarlarda verilmezse kullanılan geçmiş politikaları.
+// kline_live, ticker ve index sadece son durumu taşıdığı için geçmiş tutulmaz.
+var DefaultHistory = map[string]HistoryPolicy{
+	NamespaceKline:     {Size: 120, TTL: 2 * time.Hour},
+	NamespaceSignals:   {Size: 100, TTL: 24 * time.Hour},
+	NamespaceWallet:    {Size: 20, TTL: 24 * time.Hour},
+	NamespaceArbitrage: {Size: 100, TTL: time.Hour},
+	NamespaceAlerts:    {Size: 50, TTL: 24 * time.Hour},
+}
+
+// ChannelCatalog: Abonelikte kanal parametrelerinin doğrulandığı bilinen değerler.
+// Bilinmeyen sembol/interval'e abone olunamaz (yazım hatası sessizce boş kanal dinlemesin).
+type ChannelCatalog struct {
+	Symbols    []string // NormalizeSymbol formatında (Örn: BTCUSDT)
+	Intervals  []string // Borsa mumları ("1m") ve kendi barlarımız ("agg30s", "tick1000"...)
+	Strategies []string
+}
+
+// values: Parametrenin bilinen değerleri.
+func (c ChannelCatalog) values(param string) []string {
+	switch param {
+	case paramSymbol:
+		return c.Symbols
+	case paramInterval:
+		return c.Intervals
+	case paramStrategy:
+		return c.Strategies
+	}
+	return nil
+}
+
+// channelName: channelName("kline", "BTCUSDT", "1m") -> "kline:BTCUSDT:1m"
+func channelName(namespace string, params ...string) string {
+	return strings.Join(append([]string{namespace}, params...), ":")
+}
+
+// parseChannel: "kline:BTCUSDT:1m" -> "kline", ["BTCUSDT", "1m"]
+func parseChannel(channel string) (namespace string, params []string) {
+	namespace, rest, ok := strings.Cut(channel, ":")
+	if ok {
+		params = strings.Split(rest, ":")
+	}
+	return namespace, params
+}
+
+// authorizeSubscribe: Kanal adı geçerli mi ve kullanıcı abone olabilir mi? claims nil ise
+// bağlantı anonimdir. Bilinmeyen kanal ErrorUnknownChannel, yetkisiz ErrorPermissionDenied döner.
+func (s *SocketService) authorizeSubscribe(claims *auth.Claims, channel string) error {
+	name, params := parseChannel(channel)
+	ns, ok := namespaces[name]
+	if !ok || len(params) != len(ns.params) {
+		return centrifuge.ErrorUnknownChannel
+	}
+	for i, param := range ns.params {
+		if param == paramAccount {
+			if params[i] == "" || claims == nil || !claims.CanReadAccount(params[i]) {
+				return centrifuge.ErrorPermissionDenied
+			}
+			continue
+		}
+		if !slices.Contains(s.cfg.Catalog.values(param), params[i]) {
+			return centrifuge.ErrorUnknownChannel
+		}
+	}
+	return nil
+}
+
+// presence: Kanalın namespace'inde presence açık mı?
+func presence(channel string) bool {
+	name, _ := parseChannel(channel)
+	return namespaces[name].presence
+}
+
+// history: Kanalın namespace'ine ait geçmiş politikası.
+func (s *SocketService) history(channel string) (HistoryPolicy, bool) {
+	name, _ := parseChannel(channel)
+	policy, ok := s.cfg.History[name]
+	return policy, ok && policy.Size > 0 && policy.TTL > 0
+}
+
+// Channels: ports.ChannelDirectory. Bilinen değerlerin tüm kombinasyonlarını namespace
+// sırasıyla döner; özel kanallar sadece verilen hesaplar için listelenir.
+func (s *SocketService) Channels(accounts []string) []domain.ChannelInfo {
+	names := make([]string, 0, len(namespaces))
+	for name := range namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []domain.ChannelInfo
+	for _, name := range names {
+		ns := namespaces[name]
+		for _, params := range s.combinations(ns.params, accounts) {
+			channel := channelName(name, params...)
+			info := domain.ChannelInfo{
+				Channel:     channel,
+				Namespace:   name,
+				Description: ns.description,
+				Private:     ns.private,
+				Presence:    ns.presence,
+			}
+			if policy, ok := s.history(channel); ok {
+				info.HistorySize = policy.Size
+				info.HistoryTTLSeconds = policy.TTL.Seconds()
+			}
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// combinations: Parametrelerin bilinen değerlerinin kartezyen çarpımı.
+func (s *SocketService) combinations(params, accounts []string) [][]string {
+	out := [][]string{nil}
+	for _, param := range params {
+		values := s.cfg.Catalog.values(param)
+		if param == paramAccount {
+			values = accounts
+		}
+		var next [][]string
+		for _, prefix := range out {
+			for _, v := range values {
+				next = append(next, append(slices.Clone(prefix), v))
+			}
+		}
+		out = next
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// İstemciler api kopyalarına dağılmış olabilir; her node kendi istemcilerini bilir.
+// Admin istekleri survey ile (Redis broker varsa tüm node'lara) sorulur.
+const (
+	surveyClients    = "clients"
+	surveyDisconnect = "disconnect"
+)
+
+// Survey yanıt kodları (0: başarılı).
+const (
+	surveyCodeUnknownOp uint32 = iota + 1
+	surveyCodeNotFound
+	surveyCodeInternal
+	surveyCodeNotAvailable
+	surveyCodeRPCError
+)
+
+// disconnectByAdmin: Yeniden bağlanmayı engelleyen (4500-4999 aralığı) kapanış sebebi.
+var disconnectByAdmin = centrifuge.Disconnect{Code: 4500, Reason: "disconnected by admin"}
+
+// connectedAtKey: Bağlantı zamanı client context'inde bu anahtarla tutulur.
+type connectedAtKey struct{}
+
+// onSurvey: Diğer node'lardan (veya bu node'dan) gelen admin isteklerini ve iletilen
+// strateji RPC'lerini (rpc_forward.go) yanıtlar.
+func (s *SocketService) onSurvey(e centrifuge.SurveyEvent, cb centrifuge.SurveyCallback) {
+	switch e.Op {
+	case surveyClients:
+		data, err := json.Marshal(s.localClients())
+		if err != nil {
+			cb(centrifuge.SurveyReply{Code: surveyCodeInternal})
+			return
+		}
+		cb(centrifuge.SurveyReply{Data: data})
+	case surveyDisconnect:
+		client, ok := s.Node.Hub().Connections()[string(e.Data)]
+		if !ok {
+			cb(centrifuge.SurveyReply{Code: surveyCodeNotFound})
+			return
+		}
+		s.log.Info("istemci admin tarafından koparıldı", "client", client.ID(), "user", client.UserID())
+		client.Disconnect(disconnectByAdmin)
+		cb(centrifuge.SurveyReply{})
+	case surveyStrategyNode:
+		s.onStrategyNodeSurvey(cb)
+	case surveyRPC:
+		s.onForwardedRPC(e.Data, cb)
+	default:
+		cb(centrifuge.SurveyReply{Code: surveyCodeUnknownOp})
+	}
+}
+
+// localClients: Bu node'a bağlı istemciler.
+func (s *SocketService) localClients() []domain.ConnectedClient {
+	connections := s.Node.Hub().Connections()
+	out := make([]domain.ConnectedClient, 0, len(connections))
+	for _, client := range connections {
+		info := domain.ConnectedClient{
+			ClientID:  client.ID(),
+			User:      client.UserID(),
+			Transport: client.Transport().Name(),
+			Channels:  client.Channels(),
+			Node:      s.Node.ID(),
+		}
+		sort.Strings(info.Channels)
+		if claims := sessionClaims(client); claims != nil {
+			info.Role = claims.Role
+		}
+		if at, ok := client.Context().Value(connectedAtKey{}).(time.Time); ok {
+			info.ConnectedAt = at
+		}
+		out = append(out, info)
+	}
+	return out
+}
+
+// Clients: ports.ClientAdmin. Yanıt vermeyen node olursa hata döner (liste eksik kalmasın).
+func (s *SocketService) Clients(ctx context.Context) ([]domain.ConnectedClient, error) {
+	results, err := s.Node.Survey(ctx, surveyClients, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("istemci listesi alınamadı: %w", err)
+	}
+
+	out := []domain.ConnectedClient{}
+	for node, result := range results {
+		if result.Code != 0 {
+			return nil, fmt.Errorf("node %s istemci listesini veremedi (kod %d)", node, result.Code)
+		}
+		var clients []domain.ConnectedClient
+		if err := json.Unmarshal(result.Data, &clients); err != nil {
+			return nil, fmt.Errorf("node %s yanıtı okunamadı: %w", node, err)
+		}
+		out = append(out, clients...)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ConnectedAt.Before(out[j].ConnectedAt) })
+	return out, nil
+}
+
+// DisconnectClient: ports.ClientAdmin. İstemci hangi node'daysa orada koparılır.
+func (s *SocketService) DisconnectClient(ctx context.Context, clientID string) error {
+	results, err := s.Node.Survey(ctx, surveyDisconnect, []byte(clientID), "")
+	if err != nil {
+		return fmt.Errorf("istemci koparılamadı: %w", err)
+	}
+	for _, result := range results {
+		if result.Code == 0 {
+			return nil
+		}
+	}
+	return domain.ErrClientNotFound
+}
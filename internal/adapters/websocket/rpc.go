@@ -0,0 +1,383 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"v2-trading-bot/internal/adapters/auth"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// RPC yöntemleri. İstemci mevcut bağlantı üzerinden çağırır:
+//
+//	const { data } = await centrifuge.rpc('get_history', { symbol: 'BTCUSDT', interval: '1m', limit: 200 })
+//
+// Parametreler JSON Schema ile doğrulanır; şemaya uymayan istek 400 koduyla reddedilir.
+const (
+	RPCGetHistory        = "get_history"
+	RPCGetWallet         = "get_wallet"
+	RPCGetStrategy       = "get_strategy"
+	RPCPlaceManualOrder  = "place_manual_order"
+	RPCSetStrategyParams = "set_strategy_params"
+	RPCPauseStrategy     = "pause_strategy"
+	RPCResumeStrategy    = "resume_strategy"
+	RPCResetWallet       = "reset_wallet"
+)
+
+// Uygulamaya ait RPC hata kodları (Centrifuge 400 altını kendine ayırır).
+const (
+	rpcCodeInvalidParams = 400
+	rpcCodeNotFound      = 404
+	rpcCodeConflict      = 409
+)
+
+// rpcCall: Tek bir RPC çağrısı. claims nil ise bağlantı anonimdir.
+type rpcCall struct {
+	ctx    context.Context
+	claims *auth.Claims
+	params json.RawMessage
+}
+
+// rpcMethod: Bir RPC yönteminin tanımı.
+type rpcMethod struct {
+	// role: Çağırmak için gereken en düşük rol; boşsa anonim bağlantılar da çağırabilir.
+	role domain.Role
+	// strategy: Yöntem stratejinin çalıştığı süreçte çalışır; bu süreçte yoksa oraya iletilir.
+	strategy bool
+	schema   *jsonschema.Resolved
+	handle   func(s *SocketService, call rpcCall) (any, error)
+}
+
+// emptyParams: Parametre almayan yöntemler ({} veya boş gövde).
+const emptyParams = `{"type": "object", "additionalProperties": false}`
+
+var rpcMethods = map[string]rpcMethod{
+	// Grafik için geçmiş mumlar (eskiden yeniye). kline kanalları gibi herkese açıktır.
+	RPCGetHistory: {
+		schema: mustSchema(`{
+			"type": "object",
+			"properties": {
+				"exchange": {"type": "string", "minLength": 1},
+				"symbol":   {"type": "string", "minLength": 1},
+				"interval": {"type": "string", "minLength": 1},
+				"limit":    {"type": "integer", "minimum": 1, "maximum": 1000}
+			},
+			"required": ["symbol", "interval"],
+			"additionalProperties": false
+		}`),
+		handle: (*SocketService).rpcGetHistory,
+	},
+	RPCGetWallet: {
+		role:   domain.RoleViewer,
+		schema: mustSchema(emptyParams),
+		handle: (*SocketService).rpcGetWallet,
+	},
+	RPCGetStrategy: {
+		role:     domain.RoleViewer,
+		strategy: true,
+		schema:   mustSchema(emptyParams),
+		handle:   (*SocketService).rpcGetStrategy,
+	},
+	// Paper trading cüzdanında tüm bakiyeyle alım/satım (son kapanış fiyatından).
+	RPCPlaceManualOrder: {
+		role:     domain.RoleTrader,
+		strategy: true,
+		schema: mustSchema(`{
+			"type": "object",
+			"properties": {
+				"symbol": {"type": "string", "minLength": 1},
+				"side":   {"enum": ["BUY", "SELL"]}
+			},
+			"required": ["symbol", "side"],
+			"additionalProperties": false
+		}`),
+		handle: (*SocketService).rpcPlaceManualOrder,
+	},
+	// Verilmeyen alanlar değişmez; oversold < overbought kontrolü birleştirilmiş ayarlarda yapılır.
+	RPCSetStrategyParams: {
+		role:     domain.RoleTrader,
+		strategy: true,
+		schema: mustSchema(`{
+			"type": "object",
+			"properties": {
+				"rsi_period": {"type": "integer", "minimum": 2, "maximum": 100},
+				"oversold":   {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 100},
+				"overbought": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 100}
+			},
+			"minProperties": 1,
+			"additionalProperties": false
+		}`),
+		handle: (*SocketService).rpcSetStrategyParams,
+	},
+	RPCPauseStrategy: {
+		role:     domain.RoleTrader,
+		strategy: true,
+		schema:   mustSchema(emptyParams),
+		handle:   (*SocketService).rpcPauseStrategy,
+	},
+	RPCResumeStrategy: {
+		role:     domain.RoleTrader,
+		strategy: true,
+		schema:   mustSchema(emptyParams),
+		handle:   (*SocketService).rpcResumeStrategy,
+	},
+	// Cüzdanı başlangıç bakiyesine döndürür; yeni bakiye "wallet:<account>" kanalına da yayınlanır.
+	RPCResetWallet: {
+		role:     domain.RoleTrader,
+		strategy: true,
+		schema:   mustSchema(emptyParams),
+		handle:   (*SocketService).rpcResetWallet,
+	},
+}
+
+// mustSchema: Şema derlenemezse program başlamaz (şemalar koddadır, çalışma anında değişmez).
+func mustSchema(raw string) *jsonschema.Resolved {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(raw), &schema); err != nil {
+		panic(fmt.Sprintf("RPC şeması okunamadı: %v", err))
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		panic(fmt.Sprintf("RPC şeması derlenemedi: %v", err))
+	}
+	return resolved
+}
+
+// SetStrategyController: Strateji ve cüzdan RPC'lerinin (place_manual_order, set_strategy_params,
+// pause_strategy...) yönlendirileceği servisi ayarlar. Verilmezse (api kopyası) bu yöntemler
+// stratejiyi çalıştıran node'a iletilir; böyle bir node yoksa "not available" döner.
+func (s *SocketService) SetStrategyController(strategy ports.StrategyController) {
+	s.strategy = strategy
+}
+
+// SetCandleSource: get_history'nin mumları okuyacağı yeri ayarlar.
+func (s *SocketService) SetCandleSource(candles ports.CandleRepository) {
+	s.candles = candles
+}
+
+func rpcAllowed(m rpcMethod, claims *auth.Claims) bool {
+	return m.role == "" || (claims != nil && claims.Role.Allows(m.role))
+}
+
+// handleRPC: Yetkiyi ve parametreleri kontrol edip yöntemi çalıştırır; sonucu JSON olarak döner.
+func (s *SocketService) handleRPC(ctx context.Context, claims *auth.Claims, method string, data []byte) ([]byte, error) {
+	m, ok := rpcMethods[method]
+	if !ok {
+		return nil, centrifuge.ErrorMethodNotFound
+	}
+	if !rpcAllowed(m, claims) {
+		return nil, centrifuge.ErrorPermissionDenied
+	}
+
+	if len(data) == 0 {
+		data = []byte("{}")
+	}
+	var instance any
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return nil, invalidParams(err)
+	}
+	if err := m.schema.Validate(instance); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	if m.strategy && s.strategy == nil {
+		return s.forwardRPC(ctx, claims, method, data)
+	}
+
+	result, err := m.handle(s, rpcCall{ctx: ctx, claims: claims, params: data})
+	if err != nil {
+		return nil, s.rpcError(method, err)
+	}
+	return json.Marshal(result)
+}
+
+// rpcError: Servis hatalarını istemcinin ayırt edebileceği Centrifuge hatalarına çevirir.
+// Beklenmeyen hatalar loglanır, istemciye ayrıntı gönderilmez.
+func (s *SocketService) rpcError(method string, err error) error {
+	var cErr *centrifuge.Error
+	switch {
+	case errors.As(err, &cErr):
+		return cErr
+	case errors.Is(err, domain.ErrInsufficientBalance):
+		return &centrifuge.Error{Code: rpcCodeConflict, Message: err.Error()}
+	case errors.Is(err, domain.ErrNoPrice):
+		return &centrifuge.Error{Code: rpcCodeNotFound, Message: err.Error()}
+	case errors.Is(err, domain.ErrSymbolNotTraded):
+		return invalidParams(err)
+	}
+	s.log.Error("RPC hatası", "method", method, "err", err)
+	return centrifuge.ErrorInternal
+}
+
+func invalidParams(err error) *centrifuge.Error {
+	return &centrifuge.Error{Code: rpcCodeInvalidParams, Message: "geçersiz parametre: " + err.Error()}
+}
+
+// decodeParams: Şemadan geçmiş parametreleri yönteme ait struct'a çevirir.
+func decodeParams(call rpcCall, v any) error {
+	if err := json.Unmarshal(call.params, v); err != nil {
+		return invalidParams(err)
+	}
+	return nil
+}
+
+// strategyController: Strateji bu süreçte yoksa ErrorNotAvailable.
+func (s *SocketService) strategyController() (ports.StrategyController, error) {
+	if s.strategy == nil {
+		return nil, centrifuge.ErrorNotAvailable
+	}
+	return s.strategy, nil
+}
+
+// authorizedWallet: Kullanıcının görebildiği paper trading cüzdanı.
+func (s *SocketService) authorizedWallet(claims *auth.Claims) (*domain.Wallet, error) {
+	if s.wallets == nil {
+		return nil, centrifuge.ErrorNotAvailable
+	}
+	wallet, err := s.wallets.GetWallet()
+	if err != nil {
+		return nil, err
+	}
+	if !claims.CanReadAccount(wallet.ID) {
+		return nil, centrifuge.ErrorPermissionDenied
+	}
+	return wallet, nil
+}
+
+func (s *SocketService) rpcGetHistory(call rpcCall) (any, error) {
+	if s.candles == nil {
+		return nil, centrifuge.ErrorNotAvailable
+	}
+	params := struct {
+		Exchange string `json:"exchange"`
+		Symbol   string `json:"symbol"`
+		Interval string `json:"interval"`
+		Limit    int    `json:"limit"`
+	}{Exchange: domain.ExchangeBinance, Limit: 100}
+	if err := decodeParams(call, &params); err != nil {
+		return nil, err
+	}
+	params.Exchange = strings.ToLower(params.Exchange)
+	params.Symbol = domain.NormalizeSymbol(params.Symbol)
+	if !slices.Contains(s.cfg.Catalog.Symbols, params.Symbol) {
+		return nil, invalidParams(fmt.Errorf("bilinmeyen sembol %q", params.Symbol))
+	}
+	if !slices.Contains(s.cfg.Catalog.Intervals, params.Interval) {
+		return nil, invalidParams(fmt.Errorf("bilinmeyen interval %q", params.Interval))
+	}
+
+	candles, err := s.candles.GetLatestCandles(params.Exchange, params.Symbol, params.Interval, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	// Repository DESC döner, grafik için ASC'ye çeviriyoruz.
+	slices.Reverse(candles)
+	if candles == nil {
+		candles = []domain.Candle{}
+	}
+	return candles, nil
+}
+
+func (s *SocketService) rpcGetWallet(call rpcCall) (any, error) {
+	return s.authorizedWallet(call.claims)
+}
+
+// status: get_strategy, pause_strategy, resume_strategy ve set_strategy_params yanıtı.
+func status(strategy ports.StrategyController) domain.StrategyStatus {
+	return domain.StrategyStatus{Running: strategy.StrategyRunning(), Params: strategy.StrategyParams()}
+}
+
+func (s *SocketService) rpcGetStrategy(rpcCall) (any, error) {
+	strategy, err := s.strategyController()
+	if err != nil {
+		return nil, err
+	}
+	return status(strategy), nil
+}
+
+func (s *SocketService) rpcPlaceManualOrder(call rpcCall) (any, error) {
+	strategy, err := s.strategyController()
+	if err != nil {
+		return nil, err
+	}
+	var params struct {
+		Symbol string            `json:"symbol"`
+		Side   domain.SignalType `json:"side"`
+	}
+	if err := decodeParams(call, &params); err != nil {
+		return nil, err
+	}
+	params.Symbol = domain.NormalizeSymbol(params.Symbol)
+	if !slices.Contains(s.cfg.Catalog.Symbols, params.Symbol) {
+		return nil, invalidParams(fmt.Errorf("bilinmeyen sembol %q", params.Symbol))
+	}
+	if _, err := s.authorizedWallet(call.claims); err != nil {
+		return nil, err
+	}
+
+	return strategy.PlaceManualOrder(call.ctx, domain.ManualOrder{
+		Symbol: params.Symbol,
+		Side:   params.Side,
+		User:   call.claims.Subject,
+	})
+}
+
+func (s *SocketService) rpcSetStrategyParams(call rpcCall) (any, error) {
+	strategy, err := s.strategyController()
+	if err != nil {
+		return nil, err
+	}
+	// Güncel ayarların üzerine sadece gönderilen alanlar yazılır (birleştirme serviste atomik).
+	var patch domain.StrategyParamsPatch
+	if err := decodeParams(call, &patch); err != nil {
+		return nil, err
+	}
+	if _, err := strategy.UpdateStrategyParams(patch); err != nil {
+		return nil, invalidParams(err)
+	}
+	s.log.Info("strateji ayarları RPC ile değiştirildi", "user", call.claims.Subject)
+	return status(strategy), nil
+}
+
+func (s *SocketService) rpcPauseStrategy(call rpcCall) (any, error) {
+	strategy, err := s.strategyController()
+	if err != nil {
+		return nil, err
+	}
+	strategy.StopStrategy()
+	s.log.Info("strateji RPC ile durduruldu", "user", call.claims.Subject)
+	return status(strategy), nil
+}
+
+func (s *SocketService) rpcResumeStrategy(call rpcCall) (any, error) {
+	strategy, err := s.strategyController()
+	if err != nil {
+		return nil, err
+	}
+	strategy.StartStrategy()
+	s.log.Info("strateji RPC ile başlatıldı", "user", call.claims.Subject)
+	return status(strategy), nil
+}
+
+func (s *SocketService) rpcResetWallet(call rpcCall) (any, error) {
+	strategy, err := s.strategyController()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.authorizedWallet(call.claims); err != nil {
+		return nil, err
+	}
+	wallet, err := strategy.ResetWallet(call.ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Info("cüzdan RPC ile sıfırlandı", "user", call.claims.Subject, "account", wallet.ID)
+	return wallet, nil
+}
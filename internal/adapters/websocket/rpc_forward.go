@@ -0,0 +1,140 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"v2-trading-bot/internal/adapters/auth"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// Strateji sadece ingest sürecinde çalışır, istemciler ise api kopyalarına bağlanır.
+// api kopyası strateji RPC'lerini yetki ve şema kontrolünden sonra survey ile (Redis broker
+// üzerinden) strateji node'una iletir; yanıt aynı bağlantıdan istemciye döner.
+const (
+	surveyStrategyNode = "strategy_node"
+	surveyRPC          = "rpc"
+)
+
+// rpcForwardTimeout: Strateji node'unun bulunması ve yanıt vermesi için toplam süre.
+const rpcForwardTimeout = 5 * time.Second
+
+// forwardedRPC: Strateji node'una iletilen çağrı. Token api kopyasında doğrulandı;
+// node'lar aynı Redis'i paylaştığı için claims olduğu gibi taşınır.
+type forwardedRPC struct {
+	Method string          `json:"method"`
+	Claims *auth.Claims    `json:"claims,omitempty"`
+	Params json.RawMessage `json:"params"`
+}
+
+// rpcErrorReply: Strateji node'unda dönen Centrifuge hatası (istemciye aynen iletilir).
+type rpcErrorReply struct {
+	Code    uint32 `json:"code"`
+	Message string `json:"message"`
+}
+
+// forwardRPC: Çağrıyı strateji node'unda çalıştırır. Böyle bir node yoksa ErrorNotAvailable.
+func (s *SocketService) forwardRPC(ctx context.Context, claims *auth.Claims, method string, data []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, rpcForwardTimeout)
+	defer cancel()
+
+	target, err := s.strategyNode(ctx)
+	if err != nil {
+		s.log.Warn("strateji node'u bulunamadı", "method", method, "err", err)
+		return nil, centrifuge.ErrorNotAvailable
+	}
+
+	request, err := json.Marshal(forwardedRPC{Method: method, Claims: claims, Params: data})
+	if err != nil {
+		return nil, s.rpcError(method, err)
+	}
+	results, err := s.Node.Survey(ctx, surveyRPC, request, target)
+	if err != nil {
+		s.log.Warn("RPC strateji node'una iletilemedi", "method", method, "node", target, "err", err)
+		return nil, centrifuge.ErrorNotAvailable
+	}
+	result, ok := results[target]
+	if !ok {
+		return nil, centrifuge.ErrorNotAvailable
+	}
+
+	switch result.Code {
+	case 0:
+		return result.Data, nil
+	case surveyCodeRPCError:
+		var reply rpcErrorReply
+		if err := json.Unmarshal(result.Data, &reply); err != nil {
+			return nil, s.rpcError(method, fmt.Errorf("strateji node'u yanıtı okunamadı: %w", err))
+		}
+		return nil, &centrifuge.Error{Code: reply.Code, Message: reply.Message}
+	case surveyCodeNotAvailable:
+		return nil, centrifuge.ErrorNotAvailable
+	default:
+		return nil, s.rpcError(method, fmt.Errorf("strateji node'u %s hata kodu döndü: %d", target, result.Code))
+	}
+}
+
+// strategyNode: Stratejiyi çalıştıran node. Birden fazla varsa (yanlış kurulum) hep aynısı seçilir.
+func (s *SocketService) strategyNode(ctx context.Context) (string, error) {
+	results, err := s.Node.Survey(ctx, surveyStrategyNode, nil, "")
+	if err != nil {
+		return "", err
+	}
+	var nodes []string
+	for node, result := range results {
+		if result.Code == 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		return "", errors.New("hiçbir node strateji çalıştırmıyor")
+	}
+	sort.Strings(nodes)
+	if len(nodes) > 1 {
+		s.log.Warn("birden fazla strateji node'u var, ilki kullanılıyor", "nodes", nodes)
+	}
+	return nodes[0], nil
+}
+
+// onStrategyNodeSurvey: Strateji bu süreçteyse 0, değilse surveyCodeNotAvailable döner.
+func (s *SocketService) onStrategyNodeSurvey(cb centrifuge.SurveyCallback) {
+	if s.strategy == nil {
+		cb(centrifuge.SurveyReply{Code: surveyCodeNotAvailable})
+		return
+	}
+	cb(centrifuge.SurveyReply{})
+}
+
+// onForwardedRPC: api kopyasından gelen çağrıyı bu node'daki stratejiyle çalıştırır.
+// Veritabanı beklenebileceği için broker'ın kontrol mesajı döngüsü bloklanmaz.
+func (s *SocketService) onForwardedRPC(data []byte, cb centrifuge.SurveyCallback) {
+	if s.strategy == nil {
+		cb(centrifuge.SurveyReply{Code: surveyCodeNotAvailable})
+		return
+	}
+	var request forwardedRPC
+	if err := json.Unmarshal(data, &request); err != nil {
+		s.log.Error("iletilen RPC okunamadı", "err", err)
+		cb(centrifuge.SurveyReply{Code: surveyCodeInternal})
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcForwardTimeout)
+		defer cancel()
+
+		result, err := s.handleRPC(ctx, request.Claims, request.Method, request.Params)
+		if err == nil {
+			cb(centrifuge.SurveyReply{Data: result})
+			return
+		}
+		cErr := centrifuge.ErrorInternal
+		errors.As(err, &cErr)
+		reply, _ := json.Marshal(rpcErrorReply{Code: cErr.Code, Message: cErr.Message})
+		cb(centrifuge.SurveyReply{Code: surveyCodeRPCError, Data: reply})
+	}()
+}
@@ -0,0 +1,79 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"v2-trading-bot/internal/adapters/auth"
+	"v2-trading-bot/internal/core/domain"
+
+	"github.com/centrifugal/centrifuge"
+)
+
+// REST'in strateji uç noktaları RPC'lerle aynı yoldan çalışır: yetki ve şema kontrolü burada,
+// strateji bu süreçte değilse çağrı strateji node'una iletilir (rpc_forward.go). Böylece
+// api kopyaları da /strategy ve /wallet rotalarını sunar.
+
+// StrategyStatus: ports.StrategyAdmin
+func (s *SocketService) StrategyStatus(ctx context.Context) (domain.StrategyStatus, error) {
+	var status domain.StrategyStatus
+	err := s.callRPC(ctx, RPCGetStrategy, &status)
+	return status, err
+}
+
+// SetStrategyRunning: ports.StrategyAdmin
+func (s *SocketService) SetStrategyRunning(ctx context.Context, running bool) (domain.StrategyStatus, error) {
+	method := RPCPauseStrategy
+	if running {
+		method = RPCResumeStrategy
+	}
+	var status domain.StrategyStatus
+	err := s.callRPC(ctx, method, &status)
+	return status, err
+}
+
+// Wallet: ports.StrategyAdmin
+func (s *SocketService) Wallet(ctx context.Context) (*domain.Wallet, error) {
+	var wallet domain.Wallet
+	if err := s.callRPC(ctx, RPCGetWallet, &wallet); err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// ResetWallet: ports.StrategyAdmin
+func (s *SocketService) ResetWallet(ctx context.Context) (*domain.Wallet, error) {
+	var wallet domain.Wallet
+	if err := s.callRPC(ctx, RPCResetWallet, &wallet); err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// callRPC: Parametresiz RPC'yi context'teki kullanıcıyla çalıştırıp sonucu result'a okur.
+func (s *SocketService) callRPC(ctx context.Context, method string, result any) error {
+	data, err := s.handleRPC(ctx, auth.FromContext(ctx), method, nil)
+	if err != nil {
+		return strategyAdminError(err)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("%s yanıtı okunamadı: %w", method, err)
+	}
+	return nil
+}
+
+// strategyAdminError: Centrifuge hatasını REST'in ayırt edebileceği domain hatasına çevirir.
+func strategyAdminError(err error) error {
+	var cErr *centrifuge.Error
+	if !errors.As(err, &cErr) {
+		return err
+	}
+	switch cErr.Code {
+	case centrifuge.ErrorNotAvailable.Code:
+		return domain.ErrStrategyUnavailable
+	case centrifuge.ErrorPermissionDenied.Code:
+		return domain.ErrStrategyForbidden
+	}
+	return errors.New(cErr.Message)
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// ArbitrageOpportunity: Aynı paritenin iki borsa arasındaki fiyat farkı (ücretler düşüldükten sonra)
+// eşiği yeterince uzun süre aştığında üretilen fırsat. TradeSignal gibi yayınlanır ve saklanır.
+type ArbitrageOpportunity struct {
+	ID           int64     `json:"id,omitempty"`
+	Symbol       string    `json:"symbol"`
+	BuyExchange  string    `json:"buy_exchange"`  // Ucuz olan borsa (buradan al)
+	SellExchange string    `json:"sell_exchange"` // Pahalı olan borsa (burada sat)
+	BuyPrice     float64   `json:"buy_price"`
+	SellPrice    float64   `json:"sell_price"`
+	GrossSpread  float64   `json:"gross_spread"` // (satış - alış) / alış, ücretlerden önce
+	NetSpread    float64   `json:"net_spread"`   // Taker ücretleri ve çekim maliyeti düşülmüş hali
+	HeldSeconds  float64   `json:"held_seconds"` // Fark eşiğin üzerinde kaç saniye kaldı
+	Timestamp    time.Time `json:"timestamp"`
+	Reason       string    `json:"reason"`
+}
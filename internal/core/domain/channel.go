@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrClientNotFound: Verilen kimlikte bağlı WebSocket istemcisi yok.
+var ErrClientNotFound = errors.New("istemci bulunamadı")
+
+// ChannelInfo: Abone olunabilecek bir WebSocket kanalı (REST ile keşif için).
+type ChannelInfo struct {
+	Channel     string `json:"channel"`   // Örn: kline:BTCUSDT:1m
+	Namespace   string `json:"namespace"` // Örn: kline
+	Description string `json:"description"`
+	// Private: Sadece yetkili kullanıcıların görebildiği kanal (Örn: cüzdan).
+	Private bool `json:"private"`
+	// HistorySize / HistoryTTLSeconds: Kanal geçmişi (0 ise geçmiş ve recovery yok).
+	HistorySize       int     `json:"history_size"`
+	HistoryTTLSeconds float64 `json:"history_ttl_seconds"`
+	// Presence: Kanalı kimlerin dinlediği sorgulanabilir ve join/leave olayları yayınlanır.
+	Presence bool `json:"presence"`
+}
+
+// ConnectedClient: Bağlı bir WebSocket/SSE istemcisi (admin paneli).
+type ConnectedClient struct {
+	ClientID    string    `json:"client_id"`
+	User        string    `json:"user"` // Boşsa anonim
+	Role        Role      `json:"role,omitempty"`
+	Transport   string    `json:"transport"` // websocket, sse, http_stream
+	Channels    []string  `json:"channels"`
+	ConnectedAt time.Time `json:"connected_at"`
+	// Node: İstemcinin bağlı olduğu Centrifuge node'u (birden fazla api kopyasında).
+	Node string `json:"node"`
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NewCorrelationID: Bir mumun adaptörden strateji, sinyal, işlem ve yayına kadar izlenmesi için
+// kısa rastgele bir kimlik üretir. Loglarda "cid" alanıyla görünür.
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// StreamHealth: Tek bir borsa WebSocket bağlantısının durumu.
+type StreamHealth struct {
+	Name           string    `json:"name"` // Örn: binance.kline.BTCUSDT
+	URL            string    `json:"url"`
+	Connected      bool      `json:"connected"`
+	ConnectedAt    time.Time `json:"connected_at,omitempty"`
+	LastMessageAt  time.Time `json:"last_message_at,omitempty"`
+	LagSeconds     float64   `json:"lag_seconds"`      // Son mesajdan bu yana geçen süre
+	Messages       int64     `json:"messages"`         // Toplam mesaj sayısı
+	MessagesPerMin float64   `json:"messages_per_min"` // Son ~1 dakikadaki hız
+	Reconnects     int       `json:"reconnects"`
+	LastError      string    `json:"last_error,omitempty"`
+}
+
+// FeedHealth: Bir mum serisinin (borsa/sembol/interval) tazeliği.
+type FeedHealth struct {
+	Exchange     string    `json:"exchange"`
+	Symbol       string    `json:"symbol"`
+	Interval     string    `json:"interval"`
+	LastCandle   time.Time `json:"last_candle"`   // Son kapanan mumun açılış zamanı
+	LastReceived time.Time `json:"last_received"` // Son mumun alındığı an
+	LagSeconds   float64   `json:"lag_seconds"`
+	Stale        bool      `json:"stale"`
+	StaleCount   int       `json:"stale_count"`       // Kaç kez bayat olarak işaretlendi
+	Reconnects   int       `json:"forced_reconnects"` // Watchdog'un zorladığı yeniden bağlanmalar
+}
+
+// FeedAlert: Veri akışı bayatladığında veya geri geldiğinde üretilen uyarı.
+type FeedAlert struct {
+	Exchange string    `json:"exchange"`
+	Symbol   string    `json:"symbol"`
+	Interval string    `json:"interval"`
+	Level    string    `json:"level"` // "stale" veya "recovered"
+	Message  string    `json:"message"`
+	Time     time.Time `json:"time"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// IntervalDuration: Mum interval'inin süresi. Binance/Bybit ("1m", "4h", "1d", "1w") ve
+// OKX ("1H", "1D", "1W") yazımlarını kabul eder. Süresi sabit olmayan "1M" (ay) desteklenmez.
+func IntervalDuration(interval string) (time.Duration, error) {
+	if len(interval) < 2 {
+		return 0, fmt.Errorf("bilinmeyen interval %q", interval)
+	}
+	n, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("bilinmeyen interval %q", interval)
+	}
+	units := map[byte]time.Duration{
+		's': time.Second,
+		'm': time.Minute,
+		'h': time.Hour,
+		'H': time.Hour,
+		'd': 24 * time.Hour,
+		'D': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+		'W': 7 * 24 * time.Hour,
+	}
+	unit, ok := units[interval[len(interval)-1]]
+	if !ok {
+		return 0, fmt.Errorf("bilinmeyen interval %q", interval)
+	}
+	return time.Duration(n) * unit, nil
+}
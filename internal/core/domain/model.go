@@ -6,25 +6,46 @@ import "time"
 // Centrifugo ile frontend'e dönerken json'a çevirecegiz.
 
 type Candle struct {
-	Symbol    string    `json:"symbol"`     // Örn: BTCUSDT
-	Interval  string    `json:"interval"`   // Örn: 1m, 15m, 4h
-	Open      float64   `json:"open"`       // Açılış Fiyatı
-	High      float64   `json:"high"`       // En Yüksek
-	Low       float64   `json:"low"`        // En Düşük
-	Close     float64   `json:"close"`      // Kapanış
-	Volume    float64   `json:"volume"`     // Hacim
-	EventTime time.Time `json:"event_time"` // Mumun kapandığı/oluştuğu zaman
+	Exchange string  `json:"exchange"` // Örn: binance, bybit, okx
+	Symbol   string  `json:"symbol"`   // Örn: BTCUSDT (NormalizeSymbol formatında)
+	Interval string  `json:"interval"` // Örn: 1m, 15m, 4h
+	Open     float64 `json:"open"`     // Açılış Fiyatı
+	High     float64 `json:"high"`     // En Yüksek
+	Low      float64 `json:"low"`      // En Düşük
+	Close    float64 `json:"close"`    // Kapanış
+	Volume   float64 `json:"volume"`   // Hacim (base asset, Örn: BTC)
+
+	QuoteVolume         float64 `json:"quote_volume"`           // Quote asset hacmi (Örn: USDT)
+	TradeCount          int64   `json:"trade_count"`            // Mum içindeki işlem sayısı
+	TakerBuyBaseVolume  float64 `json:"taker_buy_base_volume"`  // Alıcı (taker) tarafındaki base hacim
+	TakerBuyQuoteVolume float64 `json:"taker_buy_quote_volume"` // Alıcı (taker) tarafındaki quote hacim
+	FirstTradeID        int64   `json:"first_trade_id"`         // Mumdaki ilk işlem ID
+	LastTradeID         int64   `json:"last_trade_id"`          // Mumdaki son işlem ID
+
+	OpenTime  time.Time `json:"open_time"`  // Mumun açıldığı an (bar sınırı, birincil anahtar)
+	CloseTime time.Time `json:"close_time"` // Mumun kapandığı an
+	IsClosed  bool      `json:"is_closed"`  // false ise mum hâlâ oluşuyor (in-progress)
+
+	// CorrelationID: Mumu adaptörden yayına kadar loglarda izlemek için (veritabanına yazılmaz).
+	CorrelationID string `json:"correlation_id,omitempty"`
 }
 
 // TradeSignal: Stratejimizin ürettiği karar.
 type TradeSignal struct {
+	Strategy  string     `json:"strategy"` // Sinyali üreten strateji (Örn: StrategyRSI)
 	Symbol    string     `json:"symbol"`
 	Action    SignalType `json:"action"` // buy,sell,hold
 	Price     float64    `json:"price"`  // Sinyalin üretildigi anki fiyat.
 	Timestamp time.Time  `json:"timestamp"`
 	Reason    string     `json:"reason"`
+
+	// CorrelationID: Sinyali üreten mumun kimliği.
+	CorrelationID string `json:"correlation_id,omitempty"`
 }
 
+// StrategyRSI: RSI aşırı alım/satım stratejisi (şu an tek otomatik strateji).
+const StrategyRSI = "rsi"
+
 // SignalType : Al veya Sat emrinin yönü
 type SignalType string
 
@@ -43,6 +64,30 @@ type Wallet struct {
 
 // cüzdan verisini tasıyacak paket
 type WalletUpdate struct {
-	USDT float64 `json:"usdt"`
-	BTC  float64 `json:"btc"`
+	Account string  `json:"account"` // Cüzdan kimliği (Wallet.ID)
+	USDT    float64 `json:"usdt"`
+	BTC     float64 `json:"btc"`
+
+	// CorrelationID: Bu güncellemeye yol açan mumun kimliği.
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// Trade: Borsadaki tek bir (birleştirilmiş) işlem. Binance'in aggTrade akışından gelir.
+type Trade struct {
+	Exchange     string    `json:"exchange"`
+	Symbol       string    `json:"symbol"`
+	AggTradeID   int64     `json:"agg_trade_id"`
+	Price        float64   `json:"price"`
+	Quantity     float64   `json:"quantity"`
+	FirstTradeID int64     `json:"first_trade_id"`
+	LastTradeID  int64     `json:"last_trade_id"`
+	Time         time.Time `json:"time"`
+	IsBuyerMaker bool      `json:"is_buyer_maker"` // true ise satıcı agresif (taker) taraftır
+}
+
+// IndexPrice: Birden fazla borsadan hesaplanan birleşik referans fiyat.
+type IndexPrice struct {
+	Candle   Candle            `json:"candle"`   // Exchange = ExchangeComposite
+	Sources  []string          `json:"sources"`  // Hesaplamaya giren borsalar
+	Excluded map[string]string `json:"excluded"` // Dışarıda bırakılan borsa -> sebep
 }
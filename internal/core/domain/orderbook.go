@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// BookSide: Emir defterinin tarafı.
+type BookSide string
+
+const (
+	SideBid BookSide = "bid" // Alış emirleri
+	SideAsk BookSide = "ask" // Satış emirleri
+)
+
+// PriceLevel: Defterdeki tek bir fiyat seviyesi.
+type PriceLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// OrderBook: Bir sembolün yerel olarak tutulan emir defteri.
+// Goroutine-safe DEĞİLDİR; eşzamanlı erişimde çağıran taraf kilitlemelidir.
+type OrderBook struct {
+	Symbol       string
+	LastUpdateID int64
+	bids         map[float64]float64
+	asks         map[float64]float64
+}
+
+// NewOrderBook: REST snapshot'ından yeni bir defter oluşturur.
+func NewOrderBook(symbol string, lastUpdateID int64, bids, asks []PriceLevel) *OrderBook {
+	b := &OrderBook{
+		Symbol:       symbol,
+		LastUpdateID: lastUpdateID,
+		bids:         make(map[float64]float64, len(bids)),
+		asks:         make(map[float64]float64, len(asks)),
+	}
+	b.Apply(bids, asks)
+	return b
+}
+
+// Apply: Fark (diff) güncellemesini uygular. Miktarı 0 olan seviye defterden silinir.
+func (b *OrderBook) Apply(bids, asks []PriceLevel) {
+	apply := func(side map[float64]float64, levels []PriceLevel) {
+		for _, l := range levels {
+			if l.Quantity == 0 {
+				delete(side, l.Price)
+			} else {
+				side[l.Price] = l.Quantity
+			}
+		}
+	}
+	apply(b.bids, bids)
+	apply(b.asks, asks)
+}
+
+// BestBid: En yüksek alış seviyesi.
+func (b *OrderBook) BestBid() (PriceLevel, bool) {
+	var best PriceLevel
+	found := false
+	for p, q := range b.bids {
+		if !found || p > best.Price {
+			best, found = PriceLevel{Price: p, Quantity: q}, true
+		}
+	}
+	return best, found
+}
+
+// BestAsk: En düşük satış seviyesi.
+func (b *OrderBook) BestAsk() (PriceLevel, bool) {
+	var best PriceLevel
+	found := false
+	for p, q := range b.asks {
+		if !found || p < best.Price {
+			best, found = PriceLevel{Price: p, Quantity: q}, true
+		}
+	}
+	return best, found
+}
+
+// DepthAt: Verilen fiyattaki miktar. Seviye yoksa 0.
+func (b *OrderBook) DepthAt(side BookSide, price float64) float64 {
+	if side == SideBid {
+		return b.bids[price]
+	}
+	return b.asks[price]
+}
+
+// Top: Bir tarafın en iyi n seviyesi (bid: büyükten küçüğe, ask: küçükten büyüğe).
+func (b *OrderBook) Top(side BookSide, n int) []PriceLevel {
+	src := b.asks
+	if side == SideBid {
+		src = b.bids
+	}
+
+	levels := make([]PriceLevel, 0, len(src))
+	for p, q := range src {
+		levels = append(levels, PriceLevel{Price: p, Quantity: q})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		if side == SideBid {
+			return levels[i].Price > levels[j].Price
+		}
+		return levels[i].Price < levels[j].Price
+	})
+	if n > 0 && len(levels) > n {
+		levels = levels[:n]
+	}
+	return levels
+}
+
+// Imbalance: İlk n seviyedeki alış/satış dengesizliği, -1 ile +1 arası.
+// +1: sadece alıcı var, -1: sadece satıcı var, 0: dengeli.
+func (b *OrderBook) Imbalance(levels int) float64 {
+	var bidQty, askQty float64
+	for _, l := range b.Top(SideBid, levels) {
+		bidQty += l.Quantity
+	}
+	for _, l := range b.Top(SideAsk, levels) {
+		askQty += l.Quantity
+	}
+	if bidQty+askQty == 0 {
+		return 0
+	}
+	return (bidQty - askQty) / (bidQty + askQty)
+}
+
+// BookTicker: Defterin tepesinin (top-of-book) özeti. Frontend'e bu yayınlanır.
+type BookTicker struct {
+	Symbol    string    `json:"symbol"`
+	BidPrice  float64   `json:"bid_price"`
+	BidQty    float64   `json:"bid_qty"`
+	AskPrice  float64   `json:"ask_price"`
+	AskQty    float64   `json:"ask_qty"`
+	Spread    float64   `json:"spread"`
+	Imbalance float64   `json:"imbalance"` // İlk 10 seviye
+	Time      time.Time `json:"time"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// QualitySeverity: Doğrulamadan geçemeyen mumun ne kadar şüpheli olduğu.
+type QualitySeverity string
+
+const (
+	// QualityRejected: Mum bozuk (high < low, sıfır fiyat, geriye giden zaman...). Saklanmaz, yayınlanmaz.
+	QualityRejected QualitySeverity = "rejected"
+	// QualityFlagged: Mum geçerli görünüyor ama şüpheli (tekrar, ani fiyat sıçraması).
+	// Saklanır ve yayınlanır ama stratejiler bu mumla karar vermez.
+	QualityFlagged QualitySeverity = "flagged"
+)
+
+// QuarantinedCandle: Karantinaya alınan mum ve nedenleri.
+type QuarantinedCandle struct {
+	ID         int64           `json:"id,omitempty"`
+	Candle     Candle          `json:"candle"`
+	Severity   QualitySeverity `json:"severity"`
+	Reasons    []string        `json:"reasons"`
+	DetectedAt time.Time       `json:"detected_at"`
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+var ErrSecretNotFound = errors.New("API anahtarı bulunamadı")
+
+// ExchangeCredentials: Borsa API anahtarının çözülmüş hali. Sadece bellekte yaşar;
+// loglanırsa veya JSON'a çevrilirse maskelenir.
+type ExchangeCredentials struct {
+	Exchange  string
+	Label     string // Aynı borsada birden fazla hesap için (Örn: "main", "sub1")
+	APIKey    string
+	APISecret string
+}
+
+// String: fmt ile yazdırılırsa sır sızmasın.
+func (c ExchangeCredentials) String() string {
+	return c.Exchange + "/" + c.Label + " " + MaskSecret(c.APIKey)
+}
+
+// GoString: %#v ile de sır görünmesin.
+func (c ExchangeCredentials) GoString() string {
+	return c.String()
+}
+
+// LogValue: slog ile loglanırsa sadece maskelenmiş anahtar görünür.
+func (c ExchangeCredentials) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("exchange", c.Exchange),
+		slog.String("label", c.Label),
+		slog.String("api_key", MaskSecret(c.APIKey)),
+	)
+}
+
+// MarshalJSON: API yanıtlarına yanlışlıkla konursa bile sır dönmez.
+func (c ExchangeCredentials) MarshalJSON() ([]byte, error) {
+	return json.Marshal(SecretInfo{Exchange: c.Exchange, Label: c.Label, APIKeyMasked: MaskSecret(c.APIKey)})
+}
+
+// EncryptedSecret: Veritabanında saklanan şifreli kayıt.
+type EncryptedSecret struct {
+	Exchange string
+	Label    string
+	// KeyVersion: Şifrelemede kullanılan master anahtarın sürümü (rotation için).
+	KeyVersion int
+	// APIKey / APISecret: nonce + AES-GCM şifreli metin.
+	APIKey    []byte
+	APISecret []byte
+	// APIKeyHint: Maskelenmiş API anahtarı (listelemek için çözmeye gerek kalmasın).
+	APIKeyHint string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// SecretInfo: API'de gösterilen tek bilgi; sır içermez.
+type SecretInfo struct {
+	Exchange     string    `json:"exchange"`
+	Label        string    `json:"label"`
+	APIKeyMasked string    `json:"api_key"`
+	KeyVersion   int       `json:"key_version,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitzero"`
+	UpdatedAt    time.Time `json:"updated_at,omitzero"`
+}
+
+// MaskSecret: "AbCdEf...7890" -> "AbCd****7890". Kısa değerlerin hiçbir kısmı gösterilmez.
+func MaskSecret(s string) string {
+	if len(s) < 12 {
+		return "****"
+	}
+	return s[:4] + "****" + s[len(s)-4:]
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// StoragePolicy: Hypertable için sıkıştırma ve saklama ayarları.
+type StoragePolicy struct {
+	CompressAfter time.Duration            // Bu süreden eski chunk'lar sıkıştırılır (0 = kapalı)
+	Retention     map[string]time.Duration // Interval -> saklama süresi (Örn: "1m" -> 30 gün)
+}
+
+// ChunkStats: Tek bir TimescaleDB chunk'ının disk kullanımı.
+type ChunkStats struct {
+	Name             string    `json:"name"`
+	RangeStart       time.Time `json:"range_start"`
+	RangeEnd         time.Time `json:"range_end"`
+	IsCompressed     bool      `json:"is_compressed"`
+	TotalBytes       int64     `json:"total_bytes"`                 // Şu anki boyut
+	BeforeBytes      int64     `json:"before_compression_bytes"`    // Sıkıştırma öncesi (sadece sıkıştırılmışlarda)
+	AfterBytes       int64     `json:"after_compression_bytes"`     // Sıkıştırma sonrası (sadece sıkıştırılmışlarda)
+	CompressionRatio float64   `json:"compression_ratio,omitempty"` // before / after
+}
+
+// StorageStats: Bir hypertable'ın özet disk kullanım raporu.
+type StorageStats struct {
+	Hypertable       string       `json:"hypertable"`
+	TotalBytes       int64        `json:"total_bytes"`
+	ChunkCount       int          `json:"chunk_count"`
+	CompressedChunks int          `json:"compressed_chunks"`
+	CompressionRatio float64      `json:"compression_ratio,omitempty"`
+	Chunks           []ChunkStats `json:"chunks"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StrategyManual: Kullanıcının elle verdiği emirler ("signals:manual" kanalında yayınlanır).
+const StrategyManual = "manual"
+
+var (
+	// ErrInsufficientBalance: Paper trading cüzdanında emir için yeterli bakiye yok.
+	ErrInsufficientBalance = errors.New("yetersiz bakiye")
+	// ErrNoPrice: Sembolün henüz hiç mumu yok; emir fiyatı belirlenemez.
+	ErrNoPrice = errors.New("sembol için fiyat yok")
+	// ErrSymbolNotTraded: Paper cüzdan tek bir coin bakiyesi tutar; sadece stratejinin sembolüyle işlem yapılır.
+	ErrSymbolNotTraded = errors.New("paper cüzdan bu sembolle işlem yapmıyor")
+	// ErrStrategyUnavailable: Stratejiyi çalıştıran süreç (ingest) bulunamadı veya yanıt vermedi.
+	ErrStrategyUnavailable = errors.New("strateji şu anda erişilebilir değil")
+	// ErrStrategyForbidden: Kullanıcının rolü veya hesabı işleme yetmiyor.
+	ErrStrategyForbidden = errors.New("bu işlem için yetkiniz yok")
+)
+
+// StrategyStatus: Stratejinin çalışma durumu ve güncel ayarları.
+type StrategyStatus struct {
+	Running bool           `json:"running"`
+	Params  StrategyParams `json:"params"`
+}
+
+// StrategyParams: RSI stratejisinin çalışma sırasında değiştirilebilen ayarları.
+type StrategyParams struct {
+	RSIPeriod  int     `json:"rsi_period"` // RSI ve SMA'nın kaç mum üzerinden hesaplanacağı
+	Oversold   float64 `json:"oversold"`   // RSI bunun altındaysa AL
+	Overbought float64 `json:"overbought"` // RSI bunun üstündeyse SAT
+}
+
+// DefaultStrategyParams: Başlangıç ayarları.
+var DefaultStrategyParams = StrategyParams{RSIPeriod: 3, Oversold: 30, Overbought: 70}
+
+// Validate: Ayarlar birlikte anlamlı mı?
+func (p StrategyParams) Validate() error {
+	if p.RSIPeriod < 2 || p.RSIPeriod > 100 {
+		return fmt.Errorf("rsi_period 2 ile 100 arasında olmalı (%d)", p.RSIPeriod)
+	}
+	if p.Oversold <= 0 || p.Overbought >= 100 || p.Oversold >= p.Overbought {
+		return fmt.Errorf("0 < oversold < overbought < 100 olmalı (%.2f, %.2f)", p.Oversold, p.Overbought)
+	}
+	return nil
+}
+
+// StrategyParamsPatch: Ayarlarda kısmi değişiklik (RPC: set_strategy_params). nil alanlar değişmez.
+type StrategyParamsPatch struct {
+	RSIPeriod  *int     `json:"rsi_period,omitempty"`
+	Oversold   *float64 `json:"oversold,omitempty"`
+	Overbought *float64 `json:"overbought,omitempty"`
+}
+
+// Apply: Verilen alanları params'ın üzerine yazar.
+func (p StrategyParamsPatch) Apply(params StrategyParams) StrategyParams {
+	if p.RSIPeriod != nil {
+		params.RSIPeriod = *p.RSIPeriod
+	}
+	if p.Oversold != nil {
+		params.Oversold = *p.Oversold
+	}
+	if p.Overbought != nil {
+		params.Overbought = *p.Overbought
+	}
+	return params
+}
+
+// ManualOrder: Kullanıcının paper trading cüzdanı için verdiği emir. Strateji sinyalleri gibi
+// tüm bakiyeyle ve sembolün son kapanış fiyatından gerçekleşir.
+type ManualOrder struct {
+	Symbol string
+	Side   SignalType // SignalBuy veya SignalSell
+	// User: Emri veren kullanıcı (sinyal açıklamasında ve loglarda görünür).
+	User string
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "strings"
+
+// Desteklenen borsaların isimleri (Candle.Exchange).
+const (
+	ExchangeBinance = "binance"
+	ExchangeBybit   = "bybit"
+	ExchangeOKX     = "okx"
+
+	// ExchangeComposite: Borsalar arası birleşik (index) fiyat serisi.
+	ExchangeComposite = "composite"
+)
+
+// knownQuotes: Sembolü base/quote olarak ayırırken denenen quote varlıkları.
+// Uzun olanlar önce gelmeli (Örn: "USDT" "USD"den önce).
+var knownQuotes = []string{"USDT", "USDC", "FDUSD", "BUSD", "TUSD", "USD", "EUR", "TRY", "BTC", "ETH", "BNB"}
+
+// NormalizeSymbol: Borsaya özgü sembol yazımını ortak formata çevirir.
+// "BTC-USDT" (OKX), "btcusdt" (Binance), "BTC/USDT" -> "BTCUSDT"
+func NormalizeSymbol(raw string) string {
+	r := strings.NewReplacer("-", "", "/", "", "_", "")
+	return strings.ToUpper(r.Replace(strings.TrimSpace(raw)))
+}
+
+// SplitSymbol: Normalize edilmiş sembolü base ve quote olarak ayırır. "BTCUSDT" -> "BTC", "USDT"
+func SplitSymbol(symbol string) (base, quote string, ok bool) {
+	symbol = NormalizeSymbol(symbol)
+	for _, q := range knownQuotes {
+		if strings.HasSuffix(symbol, q) && len(symbol) > len(q) {
+			return strings.TrimSuffix(symbol, q), q, true
+		}
+	}
+	return "", "", false
+}
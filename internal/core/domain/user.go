@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Role: Kullanıcının yetki seviyesi. Üst rol alttakilerin yapabildiği her şeyi yapabilir.
+type Role string
+
+const (
+	RoleViewer Role = "viewer" // Grafikler, sinyaller, cüzdan (sadece okuma)
+	RoleTrader Role = "trader" // + stratejiyi başlat/durdur, cüzdanı sıfırla
+	RoleAdmin  Role = "admin"  // + kullanıcı yönetimi, depolama ayarları
+)
+
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleTrader: 2,
+	RoleAdmin:  3,
+}
+
+// ParseRole: "viewer", "trader" veya "admin".
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if _, ok := roleRank[role]; !ok {
+		return "", fmt.Errorf("geçersiz rol %q (viewer, trader veya admin)", s)
+	}
+	return role, nil
+}
+
+// Allows: Bu rol, required rolünün gerektirdiği işlemleri yapabilir mi?
+// Bilinmeyen (boş) rol hiçbir şeye izin vermez.
+func (r Role) Allows(required Role) bool {
+	rank, ok := roleRank[r]
+	return ok && rank >= roleRank[required]
+}
+
+var (
+	ErrUserNotFound = errors.New("kullanıcı bulunamadı")
+	ErrUserExists   = errors.New("kullanıcı zaten var")
+)
+
+// User: Dashboard/API kullanıcısı. Şifre ve API anahtarı sadece hash olarak saklanır.
+type User struct {
+	Username string `json:"username"`
+	Role     Role   `json:"role"`
+	// Accounts: Kullanıcının görebileceği paper trading cüzdanları ("wallet:<account>" kanalları).
+	Accounts  []string  `json:"accounts"`
+	CreatedAt time.Time `json:"created_at"`
+
+	PasswordHash string `json:"-"` // bcrypt
+	HasAPIKey    bool   `json:"has_api_key"`
+}
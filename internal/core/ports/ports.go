@@ -1,6 +1,10 @@
 package ports
 
-import "v2-trading-bot/internal/core/domain"
+import (
+	"context"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+)
 
 // ---Driven Ports (Gidenler/Çıkış Kapıları)---
 // Kodumuzun dış dünyaya ihtiyaç duydugu yerler.
@@ -8,15 +12,135 @@ import "v2-trading-bot/internal/core/domain"
 // Veritabanı işlemleri için interface.
 type CandleRepository interface {
 	Save(candle domain.Candle) error
-	GetLatestCandles(symbol string, limit int) ([]domain.Candle, error)
+	// Henüz kapanmamış mumun son hali (kapanmış mumlardan ayrı saklanır).
+	SavePartial(candle domain.Candle) error
+	// En yeniden en eskiye (DESC) sıralı döner.
+	GetLatestCandles(exchange, symbol, interval string, limit int) ([]domain.Candle, error)
+}
+
+// İşlem (aggTrade) kayıtları için interface.
+type TradeRepository interface {
+	SaveTrade(trade domain.Trade) error
+}
+
+// Arbitraj fırsatlarının kaydı için interface.
+type ArbitrageRepository interface {
+	SaveOpportunity(opportunity domain.ArbitrageOpportunity) error
+	// En yeniden en eskiye sıralı döner. symbol boşsa tüm semboller.
+	GetOpportunities(symbol string, limit int) ([]domain.ArbitrageOpportunity, error)
+}
+
+// Doğrulamadan geçemeyen mumların (karantina) kaydı için interface.
+type QuarantineRepository interface {
+	SaveQuarantined(q domain.QuarantinedCandle) error
+	// En yeniden en eskiye sıralı döner. exchange/symbol boşsa filtre uygulanmaz.
+	GetQuarantined(exchange, symbol string, limit int) ([]domain.QuarantinedCandle, error)
+}
+
+// Hypertable sıkıştırma/saklama yönetimi için interface (Admin paneli).
+type StorageAdminRepository interface {
+	ApplyStoragePolicy(policy domain.StoragePolicy) error
+	GetCandleStorageStats() (*domain.StorageStats, error)
+}
+
+// Kullanıcı hesapları için interface. Bulunamazsa domain.ErrUserNotFound döner.
+type UserRepository interface {
+	// CreateUser: Kullanıcı adı alınmışsa domain.ErrUserExists döner.
+	CreateUser(user domain.User) error
+	GetUser(username string) (*domain.User, error)
+	// GetUserByAPIKey: keyHash, API anahtarının SHA-256 özetidir (anahtarın kendisi saklanmaz).
+	GetUserByAPIKey(keyHash string) (*domain.User, error)
+	ListUsers() ([]domain.User, error)
+	// SetAPIKey: Kullanıcının API anahtarını değiştirir (eskisi geçersiz olur).
+	SetAPIKey(username, keyHash string) error
+}
+
+// Şifreli borsa API anahtarlarının kaydı için interface. Bulunamazsa domain.ErrSecretNotFound döner.
+// Repository şifreleme yapmaz; sadece şifreli baytları saklar.
+type SecretRepository interface {
+	SaveSecret(secret domain.EncryptedSecret) error
+	// ReplaceSecret: Kayıt okunduğundan beri (previous) değişmediyse secret ile değiştirir.
+	// Arada değiştiyse (Örn: anahtar rotation sırasında yeniden kaydedildi) false döner.
+	ReplaceSecret(secret, previous domain.EncryptedSecret) (bool, error)
+	GetSecret(exchange, label string) (*domain.EncryptedSecret, error)
+	// ListSecrets: Borsa ve etikete göre sıralı döner.
+	ListSecrets() ([]domain.EncryptedSecret, error)
+	DeleteSecret(exchange, label string) error
+}
+
+// Borsa API anahtarlarını veren interface. Canlı işlem yapacak executor için ayrıldı; henüz
+// kullanan bir servis yok. Anahtarlar env, dosya veya config'den okunmamalı, buradan alınmalı.
+type CredentialProvider interface {
+	Credentials(exchange, label string) (domain.ExchangeCredentials, error)
 }
 
 // Mesajlaşma işlemleri için interface (Centrifugo).
 type EventBus interface {
 	PublishCandle(candle domain.Candle) error
+	PublishPartialCandle(candle domain.Candle) error
 	PublishSignal(signal domain.TradeSignal) error
 
 	PublishWallet(update domain.WalletUpdate) error
+	PublishBookTicker(ticker domain.BookTicker) error
+	PublishIndex(index domain.IndexPrice) error
+	PublishArbitrage(opportunity domain.ArbitrageOpportunity) error
+	PublishFeedAlert(alert domain.FeedAlert) error
+}
+
+// Abone olunabilecek WebSocket kanallarını listeleyen interface (REST keşif uç noktası).
+type ChannelDirectory interface {
+	// Channels: accounts, kullanıcının görebildiği cüzdanlardır (özel kanallar sadece bunlar için listelenir).
+	Channels(accounts []string) []domain.ChannelInfo
+}
+
+// Bağlı WebSocket istemcilerini listeleyen ve koparan interface (admin paneli).
+type ClientAdmin interface {
+	// Clients: Tüm node'lardaki istemciler, bağlanma zamanına göre sıralı.
+	Clients(ctx context.Context) ([]domain.ConnectedClient, error)
+	// DisconnectClient: İstemciyi yeniden bağlanmaması için koparır. Yoksa domain.ErrClientNotFound döner.
+	DisconnectClient(ctx context.Context, clientID string) error
+}
+
+// Emir defteri verisi (spread, derinlik, dengesizlik) için interface.
+// Stratejiler defterin nasıl tutulduğunu bilmeden buradan okur.
+type OrderBookProvider interface {
+	// BestBidAsk: En iyi alış ve satış. Defter henüz senkronize değilse ok=false.
+	BestBidAsk(symbol string) (bid, ask domain.PriceLevel, ok bool)
+	// DepthAt: Verilen fiyat seviyesindeki miktar.
+	DepthAt(symbol string, side domain.BookSide, price float64) float64
+	// Imbalance: İlk n seviyedeki alış/satış dengesizliği (-1..+1).
+	Imbalance(symbol string, levels int) (float64, bool)
+}
+
+// Bir bağımlılığın (veritabanı, WebSocket motoru, borsa akışı) sağlık kontrolü (/healthz, /readyz).
+type HealthCheck interface {
+	// CheckHealth: Sağlıklıysa nil, değilse sebebi döner. ctx zaman aşımına uyulmalıdır.
+	CheckHealth(ctx context.Context) error
+}
+
+// Core servislerin ürettiği metrikler için interface (Prometheus).
+type Metrics interface {
+	ObserveCandleProcessing(exchange, interval string, d time.Duration)
+	IncSignal(strategy string, side domain.SignalType)
+	IncPaperTrade(side domain.SignalType)
+	SetWalletEquity(usdt float64)
+}
+
+// Borsa bağlantılarını zorla yeniden kurmak için interface (bayat veri akışı).
+type FeedReconnector interface {
+	// ReconnectFeed: Borsa/sembole ait tüm açık bağlantıları kapatır (Listen hemen yeniden bağlanır).
+	// Yeniden bağlanmaya zorlanan bağlantı sayısını döner.
+	ReconnectFeed(exchange, symbol string) int
+}
+
+// WebSocket bağlantılarının durumunu raporlayan interface (health endpoint'i).
+type StreamHealthProvider interface {
+	StreamHealth() []domain.StreamHealth
+}
+
+// Mum serilerinin tazeliğini raporlayan interface (health endpoint'i).
+type FeedHealthProvider interface {
+	FeedHealth() []domain.FeedHealth
 }
 
 // ---Driving Ports(Gelenler/Giriş Kapıları)---
@@ -26,7 +150,66 @@ type EventBus interface {
 // Handler'lar (HTTP veya WebSocket) bu servisi çağıracak.
 type TradingService interface {
 	// Dışarıdan yeni bir mum geldiginde bu çalışacak.
-	ProcessIncomingCandle(candle domain.Candle) error
+	// ctx, adaptörde açılan trace span'ını taşır (tracing kapalıysa context.Background()).
+	ProcessIncomingCandle(ctx context.Context, candle domain.Candle) error
+	// Henüz kapanmamış (in-progress) mum geldiginde bu çalışacak. Strateji tetiklenmez.
+	ProcessPartialCandle(ctx context.Context, candle domain.Candle) error
+}
+
+// Mumları izlemek isteyen servisler için interface (Örn: borsalar arası index, arbitraj).
+// TradingService her mumu sakladıktan sonra gözlemcilere iletir. Kapanmamış mumlar da
+// (STREAM_PARTIAL_KLINES açıksa) IsClosed=false ile gelir; sadece kesinleşmiş mum isteyen filtrelemelidir.
+type CandleObserver interface {
+	OnCandle(candle domain.Candle)
+}
+
+// Piyasa verisi kaynağı (borsa adaptörü). Her borsa kendi mesaj formatını
+// domain.Candle'a çevirip TradingService'e iletir; semboller NormalizeSymbol formatındadır.
+type MarketDataSource interface {
+	// Name: Borsa ismi (domain.ExchangeBinance vb.), Candle.Exchange alanına yazılır.
+	Name() string
+	// Connect: Sembol için kline akışını başlatır. Blocking'dir, goroutine içinde çağrılmalı.
+	Connect(symbol string)
+}
+
+// İşlem (tick) akışını karşılayan servis. Kendi barlarımızı buradan üretiyoruz.
+type TradeService interface {
+	ProcessIncomingTrade(trade domain.Trade) error
+}
+
+// Stratejinin çalışmasını ve paper trading cüzdanını yöneten interface. Sadece stratejiyi
+// çalıştıran süreçte (ingest) vardır; RPC'ler buna yönlendirilir.
+type StrategyController interface {
+	// StartStrategy / StopStrategy: Durdurulmuş strateji sinyal üretmez ve işlem yapmaz;
+	// mumlar yine saklanır ve yayınlanır.
+	StartStrategy()
+	StopStrategy()
+	StrategyRunning() bool
+	// ResetWallet: Cüzdanı başlangıç bakiyesine döndürür ve yeni bakiyeyi yayınlar.
+	ResetWallet(ctx context.Context) (*domain.Wallet, error)
+	GetWallet() (*domain.Wallet, error)
+
+	StrategyParams() domain.StrategyParams
+	// UpdateStrategyParams: Değişikliği güncel ayarlarla atomik olarak birleştirir (eşzamanlı iki kısmi
+	// güncellemeden biri kaybolmaz). Birleşik ayarlar geçersizse reddedilir; yeni ayarlar bir sonraki
+	// mumdan itibaren geçerlidir.
+	UpdateStrategyParams(patch domain.StrategyParamsPatch) (domain.StrategyParams, error)
+	// PlaceManualOrder: Elle verilen emri paper trading cüzdanında gerçekleştirir. Bakiye yetmezse
+	// domain.ErrInsufficientBalance, sembolün fiyatı yoksa domain.ErrNoPrice, sembol cüzdanın
+	// sembolü değilse domain.ErrSymbolNotTraded döner.
+	PlaceManualOrder(ctx context.Context, order domain.ManualOrder) (*domain.Wallet, error)
+}
+
+// Strateji ve paper trading cüzdanı için REST interface'i. Strateji başka bir süreçte (ingest)
+// çalışabileceğinden çağrılar context'teki kullanıcıyla yapılır. Strateji süreci yoksa
+// domain.ErrStrategyUnavailable, yetki yetmezse domain.ErrStrategyForbidden döner.
+type StrategyAdmin interface {
+	StrategyStatus(ctx context.Context) (domain.StrategyStatus, error)
+	SetStrategyRunning(ctx context.Context, running bool) (domain.StrategyStatus, error)
+	// Wallet: Kullanıcının görebildiği paper trading cüzdanı.
+	Wallet(ctx context.Context) (*domain.Wallet, error)
+	// ResetWallet: Cüzdanı başlangıç bakiyesine döndürür ve yeni bakiyeyi yayınlar.
+	ResetWallet(ctx context.Context) (*domain.Wallet, error)
 }
 
 type WalletRepository interface {
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+)
+
+// AggregatorConfig: Birleşik fiyat hesaplama kuralları.
+type AggregatorConfig struct {
+	// StaleAfter: Bu süredir mum göndermeyen borsa "bayat" sayılır ve beklenmez.
+	StaleAfter time.Duration
+	// MaxDeviation: Medyandan bu orandan fazla sapan fiyat aykırı (outlier) sayılır. Örn: 0.01 = %1
+	MaxDeviation float64
+	// Grace: Mum kapandıktan sonra geç kalan borsalar için beklenen en uzun süre.
+	Grace time.Duration
+	// MinSources: Index üretmek için gereken en az borsa sayısı.
+	MinSources int
+	// Exchanges: Mum göndermesi beklenen borsalar. Açılışta henüz mum göndermemiş olsalar da
+	// StaleAfter dolana kadar beklenirler; yoksa ilk kovalar tek borsayla kapanırdı.
+	Exchanges []string
+	// Intervals: Birleştirilen kline interval'ları. Sadece Binance'ten üretilen barlar (agg30s,
+	// tick1000...) başka borsada olmadığı için atlanır. Boşsa tüm interval'lar birleştirilir.
+	Intervals []string
+}
+
+// AggregatorService: Aynı sembolün farklı borsalardaki mumlarını birleştirip
+// hacim ağırlıklı medyan ile tek bir referans fiyat (index) üretir.
+// Sonuç "composite" borsası altında ayrı bir mum serisi olarak saklanır ve "index:<symbol>" kanalına yayınlanır.
+// ports.CandleObserver'ı implemente eder.
+type AggregatorService struct {
+	repo      ports.CandleRepository
+	publisher ports.EventBus
+	cfg       AggregatorConfig
+	log       *slog.Logger
+
+	started time.Time
+
+	mu       sync.Mutex
+	buckets  map[bucketKey]*bucket
+	lastSeen map[string]map[string]time.Time // "sembol/interval" -> borsa -> son mum zamanı
+	emitted  map[bucketKey]time.Time         // Geç gelenleri yok saymak için (kapanış zamanıyla)
+}
+
+type bucketKey struct {
+	symbol   string
+	interval string
+	openTime int64
+}
+
+// series: Beklenen borsalar sembol ve interval bazında tutulur.
+func (k bucketKey) series() string {
+	return k.symbol + "/" + k.interval
+}
+
+type bucket struct {
+	closeTime time.Time
+	candles   map[string]domain.Candle // borsa -> mum
+}
+
+func NewAggregatorService(repo ports.CandleRepository, publisher ports.EventBus, cfg AggregatorConfig) *AggregatorService {
+	if cfg.MinSources <= 0 {
+		cfg.MinSources = 1
+	}
+	return &AggregatorService{
+		repo:      repo,
+		publisher: publisher,
+		cfg:       cfg,
+		log:       slog.Default(),
+		started:   time.Now(),
+		buckets:   make(map[bucketKey]*bucket),
+		lastSeen:  make(map[string]map[string]time.Time),
+		emitted:   make(map[bucketKey]time.Time),
+	}
+}
+
+// SetLogger: Servisin logger'ını ayarlar.
+func (a *AggregatorService) SetLogger(log *slog.Logger) {
+	a.log = log
+}
+
+// OnCandle: ports.CandleObserver. Mumu ilgili kovaya koyar; beklenen tüm borsalar geldiyse index'i hemen üretir.
+func (a *AggregatorService) OnCandle(candle domain.Candle) {
+	if !candle.IsClosed || candle.Exchange == domain.ExchangeComposite {
+		return
+	}
+	if len(a.cfg.Intervals) > 0 && !slices.Contains(a.cfg.Intervals, candle.Interval) {
+		return
+	}
+
+	a.mu.Lock()
+	key := bucketKey{symbol: candle.Symbol, interval: candle.Interval, openTime: candle.OpenTime.UnixMilli()}
+	if _, done := a.emitted[key]; done {
+		a.mu.Unlock()
+		a.log.Debug("geç mum, index zaten üretildi", "cid", candle.CorrelationID,
+			"exchange", candle.Exchange, "symbol", candle.Symbol, "interval", candle.Interval)
+		return
+	}
+
+	seen := a.lastSeen[key.series()]
+	if seen == nil {
+		// Henüz mum göndermemiş borsalar açılıştan itibaren StaleAfter süresince beklenir.
+		seen = make(map[string]time.Time)
+		for _, exchange := range a.cfg.Exchanges {
+			seen[exchange] = a.started
+		}
+		a.lastSeen[key.series()] = seen
+	}
+	seen[candle.Exchange] = time.Now()
+
+	b := a.buckets[key]
+	if b == nil {
+		b = &bucket{closeTime: candle.CloseTime, candles: make(map[string]domain.Candle)}
+		a.buckets[key] = b
+	}
+	b.candles[candle.Exchange] = candle
+
+	var index *domain.IndexPrice
+	if a.completeLocked(key, b) {
+		index = a.emitLocked(key, b)
+	}
+	a.mu.Unlock()
+
+	a.publish(index)
+}
+
+// Run: Tüm borsaları beklemeden, Grace süresi dolan kovaları kapatır.
+// stop kapatılana kadar bloklar; goroutine içinde çağrılmalıdır.
+func (a *AggregatorService) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			var ready []*domain.IndexPrice
+			a.mu.Lock()
+			for key, b := range a.buckets {
+				if now.After(b.closeTime.Add(a.cfg.Grace)) {
+					ready = append(ready, a.emitLocked(key, b))
+				}
+			}
+			// Geç gelen kontrolü için tutulan kayıtlar sonsuza kadar büyümesin.
+			for key, closeTime := range a.emitted {
+				if now.Sub(closeTime) > a.cfg.StaleAfter+a.cfg.Grace {
+					delete(a.emitted, key)
+				}
+			}
+			a.mu.Unlock()
+
+			for _, index := range ready {
+				a.publish(index)
+			}
+		}
+	}
+}
+
+// completeLocked: Bayat olmayan tüm borsaların mumu kovaya geldi mi?
+func (a *AggregatorService) completeLocked(key bucketKey, b *bucket) bool {
+	for exchange, last := range a.lastSeen[key.series()] {
+		if time.Since(last) > a.cfg.StaleAfter {
+			continue
+		}
+		if _, ok := b.candles[exchange]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// emitLocked: Kovayı kapatır ve index'i hesaplar. Yeterli borsa yoksa nil döner.
+func (a *AggregatorService) emitLocked(key bucketKey, b *bucket) *domain.IndexPrice {
+	delete(a.buckets, key)
+	a.emitted[key] = b.closeTime
+
+	index := Composite(b.candles, a.cfg.MaxDeviation)
+	if len(index.Sources) < a.cfg.MinSources {
+		a.log.Warn("index üretilmedi", "symbol", key.symbol, "interval", key.interval,
+			"sources", len(index.Sources), "min_sources", a.cfg.MinSources)
+		return nil
+	}
+	return &index
+}
+
+func (a *AggregatorService) publish(index *domain.IndexPrice) {
+	if index == nil {
+		return
+	}
+	if err := a.repo.Save(index.Candle); err != nil {
+		a.log.Error("index kaydedilemedi", "symbol", index.Candle.Symbol, "err", err)
+	}
+	if err := a.publisher.PublishIndex(*index); err != nil {
+		a.log.Warn("index yayını başarısız", "symbol", index.Candle.Symbol, "err", err)
+	}
+}
+
+// Composite: Borsa mumlarından birleşik mumu hesaplar.
+//  1. Kapanış fiyatlarının medyanından MaxDeviation'dan fazla sapan borsalar atılır.
+//  2. Kalanların O/H/L/C değerleri hacim ağırlıklı medyanla birleştirilir, hacimler toplanır.
+func Composite(candles map[string]domain.Candle, maxDeviation float64) domain.IndexPrice {
+	index := domain.IndexPrice{Excluded: make(map[string]string)}
+
+	// Geçersiz fiyatlar medyanı kaydırıp sağlam borsaları aykırı gösterirdi.
+	closes := make([]float64, 0, len(candles))
+	for _, c := range candles {
+		if c.Close > 0 {
+			closes = append(closes, c.Close)
+		}
+	}
+	median := weightedMedian(closes, nil)
+
+	var kept []domain.Candle
+	for exchange, c := range candles {
+		if c.Close <= 0 {
+			index.Excluded[exchange] = "geçersiz fiyat"
+			continue
+		}
+		if maxDeviation > 0 && median > 0 {
+			if dev := math.Abs(c.Close-median) / median; dev > maxDeviation {
+				index.Excluded[exchange] = fmt.Sprintf("aykırı değer (medyandan %%%.2f sapma)", dev*100)
+				continue
+			}
+		}
+		kept = append(kept, c)
+		index.Sources = append(index.Sources, exchange)
+	}
+	sort.Strings(index.Sources)
+	if len(kept) == 0 {
+		return index
+	}
+
+	field := func(get func(domain.Candle) float64) float64 {
+		values := make([]float64, len(kept))
+		weights := make([]float64, len(kept))
+		for i, c := range kept {
+			values[i] = get(c)
+			weights[i] = c.Volume
+		}
+		return weightedMedian(values, weights)
+	}
+
+	first := kept[0]
+	out := domain.Candle{
+		Exchange:  domain.ExchangeComposite,
+		Symbol:    first.Symbol,
+		Interval:  first.Interval,
+		OpenTime:  first.OpenTime,
+		CloseTime: first.CloseTime,
+		IsClosed:  true,
+		Open:      field(func(c domain.Candle) float64 { return c.Open }),
+		High:      field(func(c domain.Candle) float64 { return c.High }),
+		Low:       field(func(c domain.Candle) float64 { return c.Low }),
+		Close:     field(func(c domain.Candle) float64 { return c.Close }),
+	}
+	for _, c := range kept {
+		out.Volume += c.Volume
+		out.QuoteVolume += c.QuoteVolume
+		out.TradeCount += c.TradeCount
+		out.TakerBuyBaseVolume += c.TakerBuyBaseVolume
+		out.TakerBuyQuoteVolume += c.TakerBuyQuoteVolume
+	}
+	// Ağırlıklı medyan High < Low gibi tutarsızlık üretebilir, sınırları düzelt.
+	out.High = math.Max(out.High, math.Max(out.Open, out.Close))
+	out.Low = math.Min(out.Low, math.Min(out.Open, out.Close))
+
+	index.Candle = out
+	return index
+}
+
+// weightedMedian: Ağırlıklı medyan. weights nil ise ya da hepsi 0 ise düz medyan.
+func weightedMedian(values, weights []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	type pair struct{ v, w float64 }
+	pairs := make([]pair, len(values))
+	var total float64
+	for i, v := range values {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		pairs[i] = pair{v, w}
+		total += w
+	}
+	if total <= 0 {
+		return weightedMedian(values, nil)
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].v < pairs[j].v })
+
+	var cum float64
+	for i, p := range pairs {
+		cum += p.w
+		if cum > total/2 {
+			return p.v
+		}
+		if cum == total/2 && i+1 < len(pairs) {
+			// Tam ortada kaldıysa iki değerin ortalaması (düz medyandaki gibi).
+			return (p.v + pairs[i+1].v) / 2
+		}
+	}
+	return pairs[len(pairs)-1].v
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// exchangeCandle: Verilen borsada, minute. dakikada açılan kapanmış 1m mum.
+func exchangeCandle(exchange string, minute int, close, volume float64) domain.Candle {
+	c := vCandle(minute, close)
+	c.Exchange = exchange
+	c.Volume = volume
+	c.CloseTime = c.OpenTime.Add(time.Minute - time.Millisecond)
+	return c
+}
+
+func TestWeightedMedian(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []float64
+		weights []float64
+		want    float64
+	}{
+		{"boş", nil, nil, 0},
+		{"tek sayı", []float64{3, 1, 2}, nil, 2},
+		{"çift sayı ortalaması", []float64{4, 1, 3, 2}, nil, 2.5},
+		{"ağırlık medyanı kaydırır", []float64{1, 2, 3}, []float64{1, 1, 5}, 3},
+		{"tam ortada iki değerin ortalaması", []float64{10, 20}, []float64{2, 2}, 15},
+		{"sıfır ağırlık düz medyana döner", []float64{1, 2, 3}, []float64{0, 0, 0}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weightedMedian(tt.values, tt.weights); got != tt.want {
+				t.Fatalf("weightedMedian = %v, beklenen %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComposite(t *testing.T) {
+	tests := []struct {
+		name     string
+		candles  []domain.Candle
+		sources  []string
+		excluded string // Atılması gereken borsa
+		close    float64
+		volume   float64
+	}{
+		{
+			name: "aykırı değer atılır",
+			candles: []domain.Candle{
+				exchangeCandle("binance", 0, 100, 1),
+				exchangeCandle("bybit", 0, 100.2, 1),
+				exchangeCandle("okx", 0, 105, 1),
+			},
+			sources:  []string{"binance", "bybit"},
+			excluded: "okx",
+			close:    100.1,
+			volume:   2,
+		},
+		{
+			name: "hacim ağırlığı",
+			candles: []domain.Candle{
+				exchangeCandle("binance", 0, 100, 10),
+				exchangeCandle("bybit", 0, 100.2, 1),
+				exchangeCandle("okx", 0, 100.4, 1),
+			},
+			sources: []string{"binance", "bybit", "okx"},
+			close:   100,
+			volume:  12,
+		},
+		{
+			name: "çift sayıda kaynak",
+			candles: []domain.Candle{
+				exchangeCandle("binance", 0, 100, 1),
+				exchangeCandle("bybit", 0, 100.4, 1),
+			},
+			sources: []string{"binance", "bybit"},
+			close:   100.2,
+			volume:  2,
+		},
+		{
+			name: "geçersiz fiyat",
+			candles: []domain.Candle{
+				exchangeCandle("binance", 0, 100, 1),
+				exchangeCandle("bybit", 0, 0, 1),
+			},
+			sources:  []string{"binance"},
+			excluded: "bybit",
+			close:    100,
+			volume:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candles := make(map[string]domain.Candle)
+			for _, c := range tt.candles {
+				candles[c.Exchange] = c
+			}
+			index := Composite(candles, 0.01)
+			if !slices.Equal(index.Sources, tt.sources) {
+				t.Fatalf("kaynaklar %v, beklenen %v", index.Sources, tt.sources)
+			}
+			if tt.excluded != "" && index.Excluded[tt.excluded] == "" {
+				t.Fatalf("%s atılmalıydı: %v", tt.excluded, index.Excluded)
+			}
+			c := index.Candle
+			if c.Exchange != domain.ExchangeComposite || math.Abs(c.Close-tt.close) > 1e-9 || c.Volume != tt.volume {
+				t.Fatalf("birleşik mum %+v, beklenen close=%v volume=%v", c, tt.close, tt.volume)
+			}
+			if c.High < math.Max(c.Open, c.Close) || c.Low > math.Min(c.Open, c.Close) {
+				t.Fatalf("tutarsız birleşik mum: %+v", c)
+			}
+		})
+	}
+}
+
+func newTestAggregator(started time.Time) (*AggregatorService, *recordingBus) {
+	bus := &recordingBus{}
+	a := NewAggregatorService(&memCandles{}, bus, AggregatorConfig{
+		StaleAfter: 3 * time.Minute,
+		Grace:      5 * time.Second,
+		MinSources: 1,
+		Exchanges:  []string{"binance", "bybit", "okx"},
+		Intervals:  []string{"1m"},
+	})
+	a.started = started
+	return a, bus
+}
+
+func TestAggregatorWaitsForConfiguredExchangesAtStartup(t *testing.T) {
+	a, bus := newTestAggregator(time.Now())
+
+	// Açılışta bybit ve okx henüz mum göndermedi ama bekleniyor: tek borsayla index üretilmez.
+	a.OnCandle(exchangeCandle("binance", 0, 100, 1))
+	a.OnCandle(exchangeCandle("bybit", 0, 100.1, 1))
+	if len(bus.indexes) != 0 {
+		t.Fatalf("tüm borsalar gelmeden index üretildi: %+v", bus.indexes)
+	}
+	a.OnCandle(exchangeCandle("okx", 0, 100.2, 1))
+	if len(bus.indexes) != 1 || len(bus.indexes[0].Sources) != 3 {
+		t.Fatalf("üç kaynaklı tek index bekleniyordu: %+v", bus.indexes)
+	}
+
+	// Geç gelen mum aynı kova için ikinci index üretmez.
+	a.OnCandle(exchangeCandle("okx", 0, 100.2, 1))
+	if len(bus.indexes) != 1 {
+		t.Fatalf("geç mum yeni index üretmemeli: %d", len(bus.indexes))
+	}
+}
+
+func TestAggregatorSkipsSilentExchangesAfterStaleAfter(t *testing.T) {
+	// Açılıştan beri StaleAfter geçti ve okx hiç mum göndermedi: artık beklenmez.
+	a, bus := newTestAggregator(time.Now().Add(-time.Hour))
+
+	a.OnCandle(exchangeCandle("binance", 0, 100, 1))
+	if len(bus.indexes) != 1 {
+		t.Fatalf("sessiz borsalar beklenmemeli: %+v", bus.indexes)
+	}
+	if got := bus.indexes[0].Sources; !slices.Equal(got, []string{"binance"}) {
+		t.Fatalf("kaynaklar %v", got)
+	}
+}
+
+func TestAggregatorIgnoresSingleExchangeIntervals(t *testing.T) {
+	a, bus := newTestAggregator(time.Now().Add(-time.Hour))
+
+	bar := exchangeCandle("binance", 0, 100, 1)
+	bar.Interval = "agg30s"
+	a.OnCandle(bar)
+	a.mu.Lock()
+	buckets := len(a.buckets)
+	a.mu.Unlock()
+	if buckets != 0 || len(bus.indexes) != 0 {
+		t.Fatalf("sadece Binance'te olan interval birleştirilmemeli: %d kova, %d index", buckets, len(bus.indexes))
+	}
+	if _, ok := a.lastSeen["BTCUSDT/agg30s"]; ok {
+		t.Fatal("atlanan interval beklenen borsa listesine girmemeli")
+	}
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+)
+
+// ArbitrageConfig: Arbitraj fırsatı sayılma kuralları.
+type ArbitrageConfig struct {
+	// TakerFees: Borsa -> taker ücreti oranı (0.001 = %0.1). Listede olmayan borsa için 0.
+	TakerFees map[string]float64
+	// WithdrawCosts: Borsa -> alınan coini o borsadan çekmenin sabit maliyeti (quote, Örn: USDT).
+	WithdrawCosts map[string]float64
+	// Notional: Çekim maliyetini orana çevirmek için varsayılan işlem büyüklüğü (quote).
+	Notional float64
+	// MinNetSpread: Ücretler düşüldükten sonra gereken en düşük fark oranı (0.002 = %0.2).
+	MinNetSpread float64
+	// MinDuration: Fark en az bu kadar süre eşiğin üzerinde kalmalı (anlık sıçramalar sayılmaz).
+	MinDuration time.Duration
+	// StaleAfter: Bu süreden eski fiyat karşılaştırmaya girmez.
+	StaleAfter time.Duration
+}
+
+// ArbitrageService: Aynı paritenin borsalar arasındaki fiyat farkını sürekli izler.
+// Net fark eşiği MinDuration boyunca aşarsa bir fırsat üretir, saklar ve "arbitrage:<symbol>" kanalına yayınlar.
+// Fark eşiğin altına düşmeden aynı yön için ikinci bir fırsat üretilmez.
+// ports.CandleObserver'ı implemente eder.
+type ArbitrageService struct {
+	repo      ports.ArbitrageRepository
+	publisher ports.EventBus
+	cfg       ArbitrageConfig
+	log       *slog.Logger
+
+	mu       sync.Mutex
+	prices   map[string]map[string]quote // sembol -> borsa -> son fiyat
+	episodes map[arbKey]*episode
+}
+
+type quote struct {
+	price float64
+	at    time.Time // Alındığı an (bayatlık kontrolü)
+	// openTime ve closed: Fiyatın geldiği mum. Geç gelen eski mum yeni fiyatın üzerine yazılmaz.
+	openTime time.Time
+	closed   bool
+}
+
+// newerThan: q, candle'dan daha yeni bir mumdan mı geldi? Aynı mumun kapanmış hali, sonradan
+// gelen kapanmamış halinden yenidir.
+func (q quote) newerThan(candle domain.Candle) bool {
+	if candle.OpenTime.Equal(q.openTime) {
+		return q.closed && !candle.IsClosed
+	}
+	return candle.OpenTime.Before(q.openTime)
+}
+
+type arbKey struct {
+	symbol string
+	buy    string
+	sell   string
+}
+
+// episode: Farkın kesintisiz eşik üzerinde kaldığı dönem.
+type episode struct {
+	since   time.Time
+	emitted bool
+}
+
+func NewArbitrageService(repo ports.ArbitrageRepository, publisher ports.EventBus, cfg ArbitrageConfig) *ArbitrageService {
+	return &ArbitrageService{
+		repo:      repo,
+		publisher: publisher,
+		cfg:       cfg,
+		log:       slog.Default(),
+		prices:    make(map[string]map[string]quote),
+		episodes:  make(map[arbKey]*episode),
+	}
+}
+
+// SetLogger: Servisin logger'ını ayarlar.
+func (a *ArbitrageService) SetLogger(log *slog.Logger) {
+	a.log = log
+}
+
+// OnCandle: ports.CandleObserver. Kapanmamış mumlar da fiyatı günceller (daha sık karşılaştırma için).
+func (a *ArbitrageService) OnCandle(candle domain.Candle) {
+	if candle.Exchange == domain.ExchangeComposite || candle.Close <= 0 {
+		return
+	}
+	now := time.Now()
+
+	a.mu.Lock()
+	byExchange := a.prices[candle.Symbol]
+	if byExchange == nil {
+		byExchange = make(map[string]quote)
+		a.prices[candle.Symbol] = byExchange
+	}
+	if last, ok := byExchange[candle.Exchange]; ok && last.newerThan(candle) {
+		a.mu.Unlock()
+		a.log.Debug("eski mum, fiyat güncellenmedi", "cid", candle.CorrelationID,
+			"exchange", candle.Exchange, "symbol", candle.Symbol, "open_time", candle.OpenTime)
+		return
+	}
+	byExchange[candle.Exchange] = quote{price: candle.Close, at: now, openTime: candle.OpenTime, closed: candle.IsClosed}
+	found := a.evaluateLocked(candle.Symbol, now)
+	a.mu.Unlock()
+
+	for _, opportunity := range found {
+		a.emit(opportunity)
+	}
+}
+
+// evaluateLocked: Sembolün tüm borsa çiftlerini (her iki yönde) karşılaştırır.
+func (a *ArbitrageService) evaluateLocked(symbol string, now time.Time) []domain.ArbitrageOpportunity {
+	exchanges := make([]string, 0, len(a.prices[symbol]))
+	for exchange, q := range a.prices[symbol] {
+		if a.cfg.StaleAfter > 0 && now.Sub(q.at) > a.cfg.StaleAfter {
+			continue
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+
+	var found []domain.ArbitrageOpportunity
+	active := make(map[arbKey]bool)
+	for _, buy := range exchanges {
+		for _, sell := range exchanges {
+			if buy == sell {
+				continue
+			}
+			key := arbKey{symbol: symbol, buy: buy, sell: sell}
+			buyPrice := a.prices[symbol][buy].price
+			sellPrice := a.prices[symbol][sell].price
+			gross, net := a.spread(buy, sell, buyPrice, sellPrice)
+			if net < a.cfg.MinNetSpread {
+				continue
+			}
+			active[key] = true
+
+			ep := a.episodes[key]
+			if ep == nil {
+				ep = &episode{since: now}
+				a.episodes[key] = ep
+			}
+			held := now.Sub(ep.since)
+			if ep.emitted || held < a.cfg.MinDuration {
+				continue
+			}
+			ep.emitted = true
+
+			found = append(found, domain.ArbitrageOpportunity{
+				Symbol:       symbol,
+				BuyExchange:  buy,
+				SellExchange: sell,
+				BuyPrice:     buyPrice,
+				SellPrice:    sellPrice,
+				GrossSpread:  gross,
+				NetSpread:    net,
+				HeldSeconds:  held.Seconds(),
+				Timestamp:    now,
+				Reason: fmt.Sprintf("%s'ta al (%.2f), %s'ta sat (%.2f): net %%%.3f, %s boyunca",
+					buy, buyPrice, sell, sellPrice, net*100, held.Round(time.Second)),
+			})
+		}
+	}
+
+	// Eşiğin altına düşen (veya bayatlayan) çiftlerin dönemi biter.
+	for key := range a.episodes {
+		if key.symbol == symbol && !active[key] {
+			delete(a.episodes, key)
+		}
+	}
+	return found
+}
+
+// spread: Ücretlerden önceki ve sonraki fark oranı.
+// Notional kadar quote ile buy borsasında alınır, coin sell borsasına çekilir ve orada satılır.
+func (a *ArbitrageService) spread(buy, sell string, buyPrice, sellPrice float64) (gross, net float64) {
+	gross = (sellPrice - buyPrice) / buyPrice
+
+	notional := a.cfg.Notional
+	if notional <= 0 {
+		notional = 1000
+	}
+	units := notional * (1 - a.cfg.TakerFees[buy]) / buyPrice
+	proceeds := units*sellPrice*(1-a.cfg.TakerFees[sell]) - a.cfg.WithdrawCosts[buy]
+	net = (proceeds - notional) / notional
+	return gross, net
+}
+
+func (a *ArbitrageService) emit(opportunity domain.ArbitrageOpportunity) {
+	log := a.log.With("symbol", opportunity.Symbol, "buy", opportunity.BuyExchange, "sell", opportunity.SellExchange)
+	log.Info("arbitraj fırsatı", "net_spread", opportunity.NetSpread, "reason", opportunity.Reason)
+
+	if a.repo != nil {
+		if err := a.repo.SaveOpportunity(opportunity); err != nil {
+			log.Error("arbitraj fırsatı kaydedilemedi", "err", err)
+		}
+	}
+	if err := a.publisher.PublishArbitrage(opportunity); err != nil {
+		log.Warn("arbitraj yayını başarısız", "err", err)
+	}
+}
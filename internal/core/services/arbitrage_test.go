@@ -0,0 +1,138 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+)
+
+func TestArbitrageSpread(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       ArbitrageConfig
+		buy, sell float64
+		gross     float64
+		net       float64
+	}{
+		{"ücretsiz", ArbitrageConfig{Notional: 1000}, 100, 101, 0.01, 0.01},
+		{
+			name: "taker ücretleri",
+			cfg:  ArbitrageConfig{Notional: 1000, TakerFees: map[string]float64{"binance": 0.001, "okx": 0.002}},
+			buy:  100, sell: 101,
+			gross: 0.01,
+			net:   0.999*1.01*0.998 - 1,
+		},
+		{
+			// 1000 USDT'nin %0.5'i çekim maliyeti; fark bunu karşılamıyor.
+			name: "çekim maliyeti",
+			cfg:  ArbitrageConfig{Notional: 1000, WithdrawCosts: map[string]float64{"binance": 5}},
+			buy:  100, sell: 100.4,
+			gross: 0.004,
+			net:   (1000*1.004 - 5 - 1000) / 1000,
+		},
+		{"varsayılan notional", ArbitrageConfig{WithdrawCosts: map[string]float64{"binance": 1}}, 100, 100, 0, -0.001},
+		{"ters yön", ArbitrageConfig{Notional: 1000}, 101, 100, -1.0 / 101, -1.0 / 101},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewArbitrageService(nil, nil, tt.cfg)
+			gross, net := a.spread("binance", "okx", tt.buy, tt.sell)
+			if math.Abs(gross-tt.gross) > 1e-12 || math.Abs(net-tt.net) > 1e-12 {
+				t.Fatalf("spread = (%v, %v), beklenen (%v, %v)", gross, net, tt.gross, tt.net)
+			}
+		})
+	}
+}
+
+func TestArbitrageEvaluate(t *testing.T) {
+	base := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	cfg := ArbitrageConfig{Notional: 1000, MinNetSpread: 0.002, MinDuration: 10 * time.Second, StaleAfter: time.Minute}
+
+	type step struct {
+		at     time.Duration // base'e göre
+		prices map[string]float64
+		want   []string // Üretilmesi gereken fırsatlar ("alış>satış")
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"MinDuration dolunca tek fırsat", []step{
+			{0, map[string]float64{"binance": 100, "okx": 100.5}, nil},
+			{5 * time.Second, map[string]float64{"okx": 100.6}, nil},
+			{10 * time.Second, map[string]float64{"okx": 100.5}, []string{"binance>okx"}},
+			// Dönem sürerken tekrar üretilmez.
+			{20 * time.Second, map[string]float64{"okx": 100.7}, nil},
+		}},
+		{"eşiğin altına düşen dönem sıfırlanır", []step{
+			{0, map[string]float64{"binance": 100, "okx": 100.5}, nil},
+			{5 * time.Second, map[string]float64{"okx": 100.1}, nil},
+			{10 * time.Second, map[string]float64{"okx": 100.5}, nil},
+			{19 * time.Second, map[string]float64{"okx": 100.5}, nil},
+			{20 * time.Second, map[string]float64{"okx": 100.5}, []string{"binance>okx"}},
+		}},
+		{"iki yön ayrı izlenir", []step{
+			{0, map[string]float64{"binance": 100, "okx": 100.5}, nil},
+			{10 * time.Second, map[string]float64{"okx": 100.5}, []string{"binance>okx"}},
+			{11 * time.Second, map[string]float64{"okx": 99.5}, nil},
+			{21 * time.Second, map[string]float64{"okx": 99.5}, []string{"okx>binance"}},
+		}},
+		{"bayat fiyat karşılaştırılmaz", []step{
+			{0, map[string]float64{"binance": 100, "okx": 100.5}, nil},
+			// binance 1 dakikadır fiyat göndermedi: okx ile karşılaştırılmaz, dönem biter.
+			{61 * time.Second, map[string]float64{"okx": 100.5}, nil},
+			{62 * time.Second, map[string]float64{"binance": 100}, nil},
+			{72 * time.Second, map[string]float64{"binance": 100}, []string{"binance>okx"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewArbitrageService(nil, nil, cfg)
+			a.prices["BTCUSDT"] = make(map[string]quote)
+			for i, s := range tt.steps {
+				now := base.Add(s.at)
+				for exchange, price := range s.prices {
+					a.prices["BTCUSDT"][exchange] = quote{price: price, at: now}
+				}
+				var got []string
+				for _, o := range a.evaluateLocked("BTCUSDT", now) {
+					got = append(got, o.BuyExchange+">"+o.SellExchange)
+				}
+				if len(got) != len(s.want) || (len(got) > 0 && got[0] != s.want[0]) {
+					t.Fatalf("adım %d: fırsatlar %v, beklenen %v", i, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestArbitrageIgnoresOutOfOrderCandles(t *testing.T) {
+	a := NewArbitrageService(nil, &recordingBus{}, ArbitrageConfig{MinNetSpread: 0.002})
+
+	candle := func(minute int, close float64, closed bool) domain.Candle {
+		c := exchangeCandle("binance", minute, close, 1)
+		c.IsClosed = closed
+		return c
+	}
+	price := func() float64 {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		return a.prices["BTCUSDT"]["binance"].price
+	}
+
+	a.OnCandle(candle(1, 101, false))
+	a.OnCandle(candle(0, 100, true)) // Önceki dakikanın geç gelen kapanışı
+	if price() != 101 {
+		t.Fatalf("eski mum yeni fiyatı ezmemeli: %v", price())
+	}
+	a.OnCandle(candle(1, 102, true))
+	a.OnCandle(candle(1, 101.5, false)) // Kapanıştan sonra gelen eski canlı güncelleme
+	if price() != 102 {
+		t.Fatalf("kapanmış mumdan sonra gelen canlı güncelleme yok sayılmalı: %v", price())
+	}
+	a.OnCandle(candle(2, 103, false))
+	if price() != 103 {
+		t.Fatalf("yeni mum fiyatı güncellemeli: %v", price())
+	}
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// BarType: Tick'lerden hangi kurala göre bar üretileceği.
+type BarType string
+
+const (
+	BarTime   BarType = "time"   // Sabit zaman aralığı (Örn: 30s, 7m)
+	BarTick   BarType = "tick"   // Her N işlemde bir bar
+	BarVolume BarType = "volume" // Her N adet (base) hacimde bir bar
+	BarDollar BarType = "dollar" // Her N quote (USDT) hacimde bir bar
+)
+
+// BarSpec: Tek bir bar tanımı.
+type BarSpec struct {
+	Type      BarType
+	Interval  time.Duration // Sadece BarTime için
+	Threshold float64       // Tick/Volume/Dollar için eşik
+}
+
+// ParseBarSpec: Ayar metnini BarSpec'e çevirir.
+// Örnekler: "30s", "7m", "1h", "tick:1000", "volume:50", "dollar:1000000"
+func ParseBarSpec(s string) (BarSpec, error) {
+	s = strings.TrimSpace(s)
+	kind, value, ok := strings.Cut(s, ":")
+	if !ok {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < time.Second {
+			return BarSpec{}, fmt.Errorf("geçersiz zaman barı %q (en az 1s olmalı)", s)
+		}
+		return BarSpec{Type: BarTime, Interval: d}, nil
+	}
+
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold <= 0 {
+		return BarSpec{}, fmt.Errorf("geçersiz bar eşiği %q", s)
+	}
+	switch BarType(kind) {
+	case BarTick, BarVolume, BarDollar:
+		return BarSpec{Type: BarType(kind), Threshold: threshold}, nil
+	}
+	return BarSpec{}, fmt.Errorf("bilinmeyen bar tipi %q", kind)
+}
+
+// Label: Bar'ın candles tablosundaki "interval" değeri. Örn: "agg30s", "agg1m", "tick1000", "vol50", "dollar1000000".
+// Zaman barları "agg" önekini alır: "1m" etiketi borsa kline'larıyla aynı (time, exchange, symbol, interval)
+// anahtarını ve kline:<sembol>:1m kanalını paylaşıp gerçek kline'ların üzerine yazardı.
+func (s BarSpec) Label() string {
+	threshold := strconv.FormatFloat(s.Threshold, 'f', -1, 64)
+	switch s.Type {
+	case BarTick:
+		return "tick" + threshold
+	case BarVolume:
+		return "vol" + threshold
+	case BarDollar:
+		return "dollar" + threshold
+	}
+	return "agg" + formatInterval(s.Interval)
+}
+
+// formatInterval: 7m0s yerine 7m, 1h0m0s yerine 1h yazar (Binance interval formatına benzesin).
+func formatInterval(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return fmt.Sprintf("%ds", d/time.Second)
+}
+
+// barBuilder: Tek bir sembol + BarSpec için bar biriktirici. Goroutine-safe DEĞİLDİR,
+// çağıran taraf (BarService) kilitler.
+//
+// Zaman barları yerel saate göre değil, olay zamanına (görülen en yüksek işlem zamanı) göre kapanır:
+// pencere sonu + grace geçilince bar kapanır. Böylece pencere bitmeden hemen önce olup birkaç yüz ms
+// geç gelen işlemler bara girer, yerel saat kayması ve replay kapanışı etkilemez.
+type barBuilder struct {
+	spec  BarSpec
+	label string
+	grace time.Duration
+
+	// bar: Eşik barlarında açık bar.
+	bar *domain.Candle
+	// windows: Zaman barlarında açık pencereler (OpenTime'a göre sıralı). Grace süresince bir önceki
+	// pencere de açık kalır.
+	windows []*domain.Candle
+
+	// lastOpen: Son açılan barın OpenTime'ı. Eşik barlarında aynı milisaniyede birden fazla bar
+	// açılabilir; (time, exchange, symbol, interval) anahtarı çakışıp önceki barın üzerine yazılmasın
+	// diye yeni bar en az 1µs (Postgres timestamp çözünürlüğü) sonraya kaydırılır.
+	lastOpen time.Time
+	// closedUntil: Zaman barlarında kapanmış pencerelerin sonu. Bundan eski işlemler atılır.
+	closedUntil time.Time
+	// watermark: Görülen en yüksek işlem zamanı; watermarkAt onun görüldüğü yerel saat.
+	watermark   time.Time
+	watermarkAt time.Time
+}
+
+func newBarBuilder(spec BarSpec, grace time.Duration) *barBuilder {
+	return &barBuilder{spec: spec, label: spec.Label(), grace: grace}
+}
+
+// Add: İşlemi bara ekler. Bu işlemle kapanan barlar varsa döner. now, işlemin alındığı yerel saattir.
+func (b *barBuilder) Add(t domain.Trade, now time.Time) []domain.Candle {
+	if b.spec.Type == BarTime {
+		return b.addTimed(t, now)
+	}
+
+	if b.bar == nil {
+		b.bar = b.open(t)
+	}
+	b.apply(b.bar, t)
+
+	// Eşik barları: eşiği geçiren işlem bu bara dahildir, bar hemen kapanır.
+	if b.thresholdReached() {
+		bar := *b.bar
+		bar.IsClosed = true
+		b.bar = nil
+		return []domain.Candle{bar}
+	}
+	return nil
+}
+
+func (b *barBuilder) addTimed(t domain.Trade, now time.Time) []domain.Candle {
+	// Penceresi kapanmış (grace'ten de geç) işlem: aynı OpenTime ile yeni bar açmak, saklanan tam barın
+	// üzerine sadece geç işlemleri yazardı. İşlem trades tablosunda kalır, bara girmez.
+	if t.Time.Before(b.closedUntil) {
+		return nil
+	}
+	if t.Time.After(b.watermark) {
+		b.watermark = t.Time
+		b.watermarkAt = now
+	}
+
+	b.apply(b.window(t), t)
+	return b.closeUntil(b.watermark.Add(-b.grace))
+}
+
+// Tick: Yeni işlem gelmediği için açık kalan zaman barlarını kapatır (yedek yol). Olay zamanı, son
+// işlemden beri geçen yerel süre kadar ilerlemiş sayılır; pencere sonu + aralık + grace geçilince kapanır.
+// Eşik barları zamanla kapanmaz.
+func (b *barBuilder) Tick(now time.Time) []domain.Candle {
+	if b.spec.Type != BarTime || len(b.windows) == 0 {
+		return nil
+	}
+	eventTime := b.watermark.Add(now.Sub(b.watermarkAt))
+	return b.closeUntil(eventTime.Add(-b.grace - b.spec.Interval))
+}
+
+// window: İşlemin düştüğü zaman penceresi; yoksa açılır.
+func (b *barBuilder) window(t domain.Trade) *domain.Candle {
+	// Zaman barları epoch'a hizalı pencerelere oturur (Binance kline'ları gibi).
+	openTime := t.Time.Truncate(b.spec.Interval)
+	i := 0
+	for ; i < len(b.windows); i++ {
+		if b.windows[i].OpenTime.Equal(openTime) {
+			return b.windows[i]
+		}
+		if b.windows[i].OpenTime.After(openTime) {
+			break
+		}
+	}
+	bar := b.open(t)
+	bar.OpenTime = openTime
+	bar.CloseTime = openTime.Add(b.spec.Interval)
+	b.windows = slices.Insert(b.windows, i, bar)
+	return bar
+}
+
+// closeUntil: Sonu end'den sonra olmayan pencereleri kapatır.
+func (b *barBuilder) closeUntil(end time.Time) []domain.Candle {
+	var closed []domain.Candle
+	for len(b.windows) > 0 && !end.Before(b.windows[0].CloseTime) {
+		bar := *b.windows[0]
+		bar.IsClosed = true
+		closed = append(closed, bar)
+		b.windows = b.windows[1:]
+	}
+	// İşlemsiz geçen pencereler de kapanmış sayılır; sonradan gelen geç işlem onları açmasın.
+	if boundary := end.Truncate(b.spec.Interval); boundary.After(b.closedUntil) {
+		b.closedUntil = boundary
+	}
+	return closed
+}
+
+func (b *barBuilder) open(t domain.Trade) *domain.Candle {
+	bar := &domain.Candle{
+		Exchange:     t.Exchange,
+		Symbol:       t.Symbol,
+		Interval:     b.label,
+		Open:         t.Price,
+		High:         t.Price,
+		Low:          t.Price,
+		Close:        t.Price,
+		FirstTradeID: t.FirstTradeID,
+		OpenTime:     t.Time,
+	}
+	if b.spec.Type != BarTime && !bar.OpenTime.After(b.lastOpen) {
+		bar.OpenTime = b.lastOpen.Add(time.Microsecond)
+	}
+	b.lastOpen = bar.OpenTime
+	return bar
+}
+
+func (b *barBuilder) apply(bar *domain.Candle, t domain.Trade) {
+	if t.Price > bar.High {
+		bar.High = t.Price
+	}
+	if t.Price < bar.Low {
+		bar.Low = t.Price
+	}
+	bar.Close = t.Price
+
+	quote := t.Price * t.Quantity
+	bar.Volume += t.Quantity
+	bar.QuoteVolume += quote
+	bar.TradeCount += t.LastTradeID - t.FirstTradeID + 1
+	if !t.IsBuyerMaker {
+		// Alıcı taker ise agresif alım.
+		bar.TakerBuyBaseVolume += t.Quantity
+		bar.TakerBuyQuoteVolume += quote
+	}
+	bar.LastTradeID = t.LastTradeID
+
+	if b.spec.Type != BarTime {
+		// OpenTime kaydırılmış olabilir; kapanış açılıştan önce görünmesin.
+		bar.CloseTime = t.Time
+		if bar.CloseTime.Before(bar.OpenTime) {
+			bar.CloseTime = bar.OpenTime
+		}
+	}
+}
+
+func (b *barBuilder) thresholdReached() bool {
+	switch b.spec.Type {
+	case BarTick:
+		return float64(b.bar.TradeCount) >= b.spec.Threshold
+	case BarVolume:
+		return b.bar.Volume >= b.spec.Threshold
+	case BarDollar:
+		return b.bar.QuoteVolume >= b.spec.Threshold
+	}
+	return false
+}
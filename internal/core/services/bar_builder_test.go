@@ -0,0 +1,208 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+)
+
+var barBase = time.Date(2024, 6, 10, 6, 0, 0, 0, time.UTC)
+
+// barTrade: barBase'ten ms sonra olan, id'li tek işlemlik aggTrade.
+func barTrade(id int64, ms int, price, qty float64) domain.Trade {
+	return domain.Trade{
+		Exchange:     domain.ExchangeBinance,
+		Symbol:       "BTCUSDT",
+		AggTradeID:   id,
+		Price:        price,
+		Quantity:     qty,
+		FirstTradeID: id,
+		LastTradeID:  id,
+		Time:         barBase.Add(time.Duration(ms) * time.Millisecond),
+	}
+}
+
+// barStep: Ya bir işlem (wall: alındığı yerel saat) ya da tick (Tick(barBase+tick)) ve sonunda kapanması beklenen barlar.
+type barStep struct {
+	trade domain.Trade
+	wall  time.Duration
+	tick  time.Duration // > 0 ise işlem yerine Tick çağrılır
+	want  []barWant
+}
+
+type barWant struct {
+	open   time.Duration // barBase'e göre
+	close  time.Duration // CloseTime, barBase'e göre
+	price  float64       // Close
+	volume float64
+	trades int64
+}
+
+func TestBarBuilder(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name  string
+		spec  string
+		grace time.Duration
+		steps []barStep
+	}{
+		{
+			name:  "zaman barı olay zamanıyla grace sonrası kapanır",
+			spec:  "30s",
+			grace: 2 * time.Second,
+			steps: []barStep{
+				{trade: barTrade(1, 1000, 100, 1), wall: 1000 * ms},
+				{trade: barTrade(2, 29900, 101, 1), wall: 29950 * ms},
+				// Yeni pencere açıldı ama grace dolmadı: önceki pencere açık kalır.
+				{trade: barTrade(3, 30500, 102, 1), wall: 30600 * ms},
+				// Pencere bitmeden olmuş ama geç gelmiş işlem hâlâ bara girer.
+				{trade: barTrade(4, 29990, 103, 1), wall: 31000 * ms},
+				{trade: barTrade(5, 32100, 104, 1), wall: 32200 * ms, want: []barWant{
+					{open: 0, close: 30 * time.Second, price: 103, volume: 3, trades: 3},
+				}},
+			},
+		},
+		{
+			name:  "grace'ten de geç işlem atılır",
+			spec:  "30s",
+			grace: 2 * time.Second,
+			steps: []barStep{
+				{trade: barTrade(1, 1000, 100, 1), wall: 1000 * ms},
+				{trade: barTrade(2, 33000, 101, 1), wall: 33000 * ms, want: []barWant{
+					{open: 0, close: 30 * time.Second, price: 100, volume: 1, trades: 1},
+				}},
+				// Kapanmış pencereye düşen işlem: saklanan tam barın üzerine yazacak yeni bar açılmaz.
+				{trade: barTrade(3, 10000, 90, 5), wall: 33100 * ms},
+				{trade: barTrade(4, 62000, 102, 1), wall: 62000 * ms, want: []barWant{
+					{open: 30 * time.Second, close: time.Minute, price: 101, volume: 1, trades: 1},
+				}},
+			},
+		},
+		{
+			name:  "işlemsiz pencereye geç işlem bar açmaz",
+			spec:  "30s",
+			grace: 2 * time.Second,
+			steps: []barStep{
+				{trade: barTrade(1, 1000, 100, 1), wall: 1000 * ms},
+				{trade: barTrade(2, 65000, 101, 1), wall: 65000 * ms, want: []barWant{
+					{open: 0, close: 30 * time.Second, price: 100, volume: 1, trades: 1},
+				}},
+				{trade: barTrade(3, 45000, 99, 1), wall: 65100 * ms},
+			},
+		},
+		{
+			name:  "tick yedek yolu: pencere sonu + aralık + grace",
+			spec:  "30s",
+			grace: 2 * time.Second,
+			steps: []barStep{
+				{trade: barTrade(1, 5000, 100, 1), wall: 5000 * ms},
+				{tick: 40 * time.Second},
+				{tick: 61 * time.Second},
+				{tick: 62 * time.Second, want: []barWant{
+					{open: 0, close: 30 * time.Second, price: 100, volume: 1, trades: 1},
+				}},
+			},
+		},
+		{
+			name:  "replay: yerel saat ilerideyken bar hemen kapanmaz",
+			spec:  "30s",
+			grace: 2 * time.Second,
+			steps: []barStep{
+				{trade: barTrade(1, 5000, 100, 1), wall: time.Hour},
+				{tick: time.Hour + time.Second},
+				{trade: barTrade(2, 20000, 101, 1), wall: time.Hour + 2*time.Second},
+				{trade: barTrade(3, 32000, 102, 1), wall: time.Hour + 3*time.Second, want: []barWant{
+					{open: 0, close: 30 * time.Second, price: 101, volume: 2, trades: 2},
+				}},
+			},
+		},
+		{
+			name: "tick barı eşiği geçiren işlemle kapanır",
+			spec: "tick:3",
+			steps: []barStep{
+				{trade: domain.Trade{Price: 100, Quantity: 1, FirstTradeID: 1, LastTradeID: 2, Time: barBase}},
+				{trade: barTrade(3, 500, 101, 1), want: []barWant{
+					{open: 0, close: 500 * ms, price: 101, volume: 2, trades: 3},
+				}},
+				{trade: barTrade(4, 600, 102, 1)},
+			},
+		},
+		{
+			name: "aynı milisaniyede açılan eşik barları 1µs kaydırılır",
+			spec: "tick:1",
+			steps: []barStep{
+				{trade: barTrade(1, 0, 100, 1), want: []barWant{{open: 0, close: 0, price: 100, volume: 1, trades: 1}}},
+				{trade: barTrade(2, 0, 101, 1), want: []barWant{
+					{open: time.Microsecond, close: time.Microsecond, price: 101, volume: 1, trades: 1},
+				}},
+				{trade: barTrade(3, 0, 102, 1), want: []barWant{
+					{open: 2 * time.Microsecond, close: 2 * time.Microsecond, price: 102, volume: 1, trades: 1},
+				}},
+				{trade: barTrade(4, 1, 103, 1), want: []barWant{{open: ms, close: ms, price: 103, volume: 1, trades: 1}}},
+			},
+		},
+		{
+			name: "hacim barı",
+			spec: "volume:1",
+			steps: []barStep{
+				{trade: barTrade(1, 0, 100, 0.4)},
+				{trade: barTrade(2, 100, 101, 0.4)},
+				{trade: barTrade(3, 200, 102, 0.5), want: []barWant{
+					{open: 0, close: 200 * ms, price: 102, volume: 1.3, trades: 3},
+				}},
+			},
+		},
+		{
+			name: "dolar barı",
+			spec: "dollar:100",
+			steps: []barStep{
+				{trade: barTrade(1, 0, 50, 1)},
+				{trade: barTrade(2, 100, 50, 1), want: []barWant{
+					{open: 0, close: 100 * ms, price: 50, volume: 2, trades: 2},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseBarSpec(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := newBarBuilder(spec, tt.grace)
+
+			for i, step := range tt.steps {
+				var got []domain.Candle
+				if step.tick > 0 {
+					got = b.Tick(barBase.Add(step.tick))
+				} else {
+					got = b.Add(step.trade, barBase.Add(step.wall))
+				}
+
+				if len(got) != len(step.want) {
+					t.Fatalf("adım %d: %d bar kapanmalıydı, %d kapandı: %+v", i, len(step.want), len(got), got)
+				}
+				for j, want := range step.want {
+					bar := got[j]
+					if !bar.IsClosed || bar.Interval != spec.Label() {
+						t.Errorf("adım %d: bar kapalı ve %q etiketli olmalı: %+v", i, spec.Label(), bar)
+					}
+					if !bar.OpenTime.Equal(barBase.Add(want.open)) || !bar.CloseTime.Equal(barBase.Add(want.close)) {
+						t.Errorf("adım %d: zaman %s-%s, beklenen %s-%s", i, bar.OpenTime.Sub(barBase),
+							bar.CloseTime.Sub(barBase), want.open, want.close)
+					}
+					if bar.Close != want.price || bar.TradeCount != want.trades || !closeEnough(bar.Volume, want.volume) {
+						t.Errorf("adım %d: close=%v trades=%d volume=%v, beklenen close=%v trades=%d volume=%v", i,
+							bar.Close, bar.TradeCount, bar.Volume, want.price, want.trades, want.volume)
+					}
+				}
+			}
+		})
+	}
+}
+
+func closeEnough(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+)
+
+// BarService: aggTrade işlemlerini saklar ve onlardan kendi barlarımızı üretir.
+// Üretilen barlar, Binance kline'ları ile AYNI yoldan (ProcessIncomingCandle) geçer;
+// yani saklanır, yayınlanır ve stratejiye girer.
+// ports.TradeService'i implemente eder.
+type BarService struct {
+	trades ports.TradeRepository
+	sink   ports.TradingService
+	specs  []BarSpec
+	grace  time.Duration
+	log    *slog.Logger
+
+	mu       sync.Mutex
+	builders map[string][]*barBuilder // sembol -> her BarSpec için bir builder
+}
+
+func NewBarService(trades ports.TradeRepository, sink ports.TradingService, specs []BarSpec) *BarService {
+	return &BarService{
+		trades:   trades,
+		sink:     sink,
+		specs:    specs,
+		grace:    defaultBarGrace,
+		log:      slog.Default(),
+		builders: make(map[string][]*barBuilder),
+	}
+}
+
+// defaultBarGrace: Zaman barı penceresi bittikten sonra geç gelen işlemler için beklenen olay zamanı süresi.
+const defaultBarGrace = 2 * time.Second
+
+// SetGracePeriod: Zaman barlarının pencere sonundan ne kadar sonra (olay zamanıyla) kapanacağını ayarlar.
+// Sadece sonradan açılan builder'ları etkiler; Run'dan önce çağrılmalıdır.
+func (s *BarService) SetGracePeriod(d time.Duration) {
+	s.grace = d
+}
+
+// SetLogger: Servisin logger'ını ayarlar.
+func (s *BarService) SetLogger(log *slog.Logger) {
+	s.log = log
+}
+
+// ProcessIncomingTrade: İşlemi saklar ve tüm bar builder'larına besler.
+func (s *BarService) ProcessIncomingTrade(trade domain.Trade) error {
+	if s.trades != nil {
+		if err := s.trades.SaveTrade(trade); err != nil {
+			// Kayıt hatası bar üretimini durdurmasın.
+			s.log.Error("işlem kaydedilemedi", "symbol", trade.Symbol, "err", err)
+		}
+	}
+
+	s.mu.Lock()
+	builders, ok := s.builders[trade.Symbol]
+	if !ok {
+		for _, spec := range s.specs {
+			builders = append(builders, newBarBuilder(spec, s.grace))
+		}
+		s.builders[trade.Symbol] = builders
+	}
+
+	now := time.Now()
+	var closed []domain.Candle
+	for _, b := range builders {
+		closed = append(closed, b.Add(trade, now)...)
+	}
+	s.mu.Unlock()
+
+	s.emit(closed)
+	return nil
+}
+
+// Run: Zaman barlarını, yeni işlem gelmese bile (olay zamanı ilerlemediği için) süresi dolunca kapatır.
+// stop kapatılana kadar bloklar; goroutine içinde çağrılmalıdır.
+func (s *BarService) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			var closed []domain.Candle
+			for _, builders := range s.builders {
+				for _, b := range builders {
+					closed = append(closed, b.Tick(now)...)
+				}
+			}
+			s.mu.Unlock()
+
+			s.emit(closed)
+		}
+	}
+}
+
+func (s *BarService) emit(bars []domain.Candle) {
+	for _, bar := range bars {
+		bar.CorrelationID = domain.NewCorrelationID()
+		if err := s.sink.ProcessIncomingCandle(context.Background(), bar); err != nil {
+			s.log.Error("bar işlenemedi", "cid", bar.CorrelationID, "symbol", bar.Symbol,
+				"interval", bar.Interval, "err", err)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"time"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// noopMetrics: Metrik toplanmıyorsa (SetMetrics çağrılmadıysa) kullanılan boş ports.Metrics.
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveCandleProcessing(string, string, time.Duration) {}
+func (noopMetrics) IncSignal(string, domain.SignalType)                   {}
+func (noopMetrics) IncPaperTrade(domain.SignalType)                       {}
+func (noopMetrics) SetWalletEquity(float64)                               {}
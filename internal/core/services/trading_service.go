@@ -1,46 +1,214 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
 	"v2-trading-bot/internal/core/domain"
 	"v2-trading-bot/internal/core/ports"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 type TradingService struct {
 	repo       ports.CandleRepository
 	publisher  ports.EventBus
 	walletRepo ports.WalletRepository
+
+	// orderBook: Opsiyonel. Varsa strateji spread ve defter dengesizliğini de görür.
+	orderBook ports.OrderBookProvider
+
+	// strategyExchange: Strateji (ve paper trading) sadece bu borsanın mumlarıyla çalışır.
+	// Diğer borsaların mumları saklanır ve yayınlanır ama karar üretmez.
+	strategyExchange string
+	// strategySymbol / strategyInterval: Strateji tek bir seride karar verir. Paper cüzdan tek bir coin
+	// bakiyesi tuttuğu için ETHUSDT alımı BTC bakiyesine eklenirdi; tick/volume barları da karar üretmez.
+	strategySymbol   string
+	strategyInterval string
+	// strategyStopped: true ise strateji sinyal üretmez (REST: POST /strategy/stop).
+	strategyStopped atomic.Bool
+	// suspectOpen: Strateji serisindeki son şüpheli (işaretlenmiş) mumun OpenTime'ı (UnixMicro).
+	// Şüpheli mum saklandığı için sonraki mumların geçmişine girer; RSI penceresinden çıkana kadar karar verilmez.
+	suspectOpen atomic.Int64
+	// replay: true ise strateji kararları sadece loglanır; sinyal yayınlanmaz, paper trade yapılmaz.
+	// Kayıt oynatılırken canlı 'demo' cüzdanı ve canlı kanallar değişmesin diye.
+	replay bool
+	// params: Stratejinin ayarları. Çalışırken değiştirilebilir (RPC: set_strategy_params).
+	params atomic.Pointer[domain.StrategyParams]
+
+	// observers: Her mumdan haberdar edilen servisler (index, arbitraj...).
+	observers []ports.CandleObserver
+	// monitors: Mum geldiği anda, doğrulama ve kayıttan önce haberdar edilen servisler (watchdog).
+	monitors []ports.CandleObserver
+
+	// walletMu: Cüzdanın oku-değiştir-yaz adımlarını sıraya koyar. Strateji (mum goroutine'i),
+	// manuel emir ve sıfırlama (RPC/REST) aynı anda çalışabilir; kilitsiz iki alım aynı USDT'yi harcardı.
+	walletMu sync.Mutex
+
+	// validator: Opsiyonel. Varsa mumlar saklanmadan önce kontrol edilir, şüpheliler karantinaya yazılır.
+	validator  *CandleValidator
+	quarantine ports.QuarantineRepository
+
+	metrics ports.Metrics
+	log     *slog.Logger
 }
 
 // NewTradingService : Servisi oluşturmak için kullanılan "constructor" fonksiyonudur.
 func NewTradingService(repo ports.CandleRepository, walletRepo ports.WalletRepository, publisher ports.EventBus) *TradingService {
-	return &TradingService{
+	s := &TradingService{
 		repo:       repo,
 		publisher:  publisher,
 		walletRepo: walletRepo,
+
+		strategyExchange: domain.ExchangeBinance,
+		strategySymbol:   "BTCUSDT",
+		strategyInterval: "1m",
+		metrics:          noopMetrics{},
+		log:              slog.Default(),
 	}
+	params := domain.DefaultStrategyParams
+	s.params.Store(&params)
+	return s
+}
+
+// SetLogger: Servisin logger'ını ayarlar.
+func (s *TradingService) SetLogger(log *slog.Logger) {
+	s.log = log
+}
+
+// SetMetrics: İşleme süresi, sinyal ve paper trade metriklerinin yazılacağı yeri ayarlar.
+func (s *TradingService) SetMetrics(metrics ports.Metrics) {
+	s.metrics = metrics
+}
+
+// AddObserver: Mumları izleyecek bir servis ekler. Başlangıçta çağrılmalıdır.
+func (s *TradingService) AddObserver(observer ports.CandleObserver) {
+	s.observers = append(s.observers, observer)
+}
+
+// AddMonitor: Akışı izleyen bir servis ekler (Örn: FeedWatchdog). Observer'lardan farkı, mum
+// doğrulama ve kayıttan önce iletilir: veritabanı kesintisi veya reddedilen mumlar sağlıklı
+// bir akışı bayat göstermez. Başlangıçta çağrılmalıdır.
+func (s *TradingService) AddMonitor(monitor ports.CandleObserver) {
+	s.monitors = append(s.monitors, monitor)
+}
+
+// SetValidator: Mum doğrulamasını açar. quarantine nil olabilir (o zaman sadece loglanır).
+func (s *TradingService) SetValidator(validator *CandleValidator, quarantine ports.QuarantineRepository) {
+	s.validator = validator
+	s.quarantine = quarantine
+}
+
+// SetStrategyExchange: Stratejinin hangi borsanın mumlarıyla çalışacağını ayarlar.
+func (s *TradingService) SetStrategyExchange(exchange string) {
+	s.strategyExchange = exchange
+}
+
+// SetStrategyMarket: Stratejinin karar verdiği sembol ve interval'i ayarlar (varsayılan BTCUSDT/1m).
+func (s *TradingService) SetStrategyMarket(symbol, interval string) {
+	s.strategySymbol = domain.NormalizeSymbol(symbol)
+	s.strategyInterval = interval
+}
+
+// SetReplay: Replay modunu açar. Başlangıçta çağrılmalıdır.
+func (s *TradingService) SetReplay(enabled bool) {
+	s.replay = enabled
+}
+
+// SetOrderBook: Stratejiye emir defteri verisini (spread, dengesizlik) açar.
+func (s *TradingService) SetOrderBook(book ports.OrderBookProvider) {
+	s.orderBook = book
 }
 
-func (s *TradingService) ProcessIncomingCandle(candle domain.Candle) error {
-	// 1. Log ve Yayın (Mevcut kodlar)
-	// fmt.Printf(...) kaldırabilirsin, kirlilik yapmasın.
+func (s *TradingService) ProcessIncomingCandle(ctx context.Context, candle domain.Candle) (err error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.ObserveCandleProcessing(candle.Exchange, candle.Interval, time.Since(start))
+	}()
+
+	// Aynı kararın tüm adımları (strateji, sinyal, işlem, yayın) loglarda "cid" ile bulunabilsin.
+	if candle.CorrelationID == "" {
+		candle.CorrelationID = domain.NewCorrelationID()
+	}
+	ctx, span := startSpan(ctx, "ProcessIncomingCandle",
+		attribute.String("exchange", candle.Exchange),
+		attribute.String("symbol", candle.Symbol),
+		attribute.String("interval", candle.Interval),
+		attribute.String("cid", candle.CorrelationID))
+	defer func() { endSpan(span, err) }()
+
+	log := s.log.With("cid", candle.CorrelationID, "exchange", candle.Exchange,
+		"symbol", candle.Symbol, "interval", candle.Interval)
+	log.Debug("mum işleniyor", "open_time", candle.OpenTime, "close", candle.Close)
+
+	for _, monitor := range s.monitors {
+		monitor.OnCandle(candle)
+	}
+
+	// Bozuk mum hiç saklanmaz; şüpheli mum saklanır ama stratejiye gitmez (RSI penceresinden çıkana kadar
+	// sonraki mumlar da karar üretmez).
+	suspect := false
+	if s.validator != nil {
+		_, validate := startSpan(ctx, "validate")
+		severity, reasons := s.validator.Check(candle)
+		validate.SetAttributes(attribute.String("severity", string(severity)))
+		validate.End()
+		if severity != "" {
+			s.quarantineCandle(log, candle, severity, reasons)
+		}
+		if severity == domain.QualityRejected {
+			return nil
+		}
+		suspect = severity == domain.QualityFlagged
+	}
 
 	// Veritabanına kaydet
-	err := s.repo.Save(candle)
+	_, save := startSpan(ctx, "save", attribute.String("db.operation", "save_candle"))
+	err = s.repo.Save(candle)
+	endSpan(save, err)
 	if err != nil {
 		return fmt.Errorf("veritabanı kayıt hatası: %v", err)
 	}
 
 	// Frontend'e canlı mumu gönder
-	_ = s.publisher.PublishCandle(candle)
+	_, publish := startSpan(ctx, "publish", attribute.String("channel", "kline"))
+	publishErr := s.publisher.PublishCandle(candle)
+	endSpan(publish, publishErr)
+	if publishErr != nil {
+		log.Warn("mum yayınlanamadı", "err", publishErr)
+	}
+
+	if suspect {
+		if s.isStrategySeries(candle) {
+			s.suspectOpen.Store(candle.OpenTime.UnixMicro())
+		}
+		return nil
+	}
+
+	for _, observer := range s.observers {
+		observer.OnCandle(candle)
+	}
 
 	// --- STRATEJİ BÖLÜMÜ (YENİ EKLENEN KISIM) ---
+	if !s.isStrategySeries(candle) || s.strategyStopped.Load() {
+		return nil
+	}
+
+	// Ayarlar mum başında bir kez okunur; işlem sırasında değişse de bu mum eski ayarlarla biter.
+	params := s.StrategyParams()
 
 	// 2. Analiz için geçmiş veriyi çek (Örn: Son 20 mum lazım)
-	// Stratejimiz RSI(14) kullanacak, o yüzden en az 15-20 mum lazım.
-	pastCandles, err := s.repo.GetLatestCandles(candle.Symbol, 20)
-	if err != nil {
-		fmt.Printf("Geçmiş veri çekilemedi: %v\n", err)
+	// RSI(n) için en az n+1 mum lazım.
+	_, history := startSpan(ctx, "history fetch", attribute.String("db.operation", "get_latest_candles"))
+	pastCandles, historyErr := s.repo.GetLatestCandles(candle.Exchange, candle.Symbol, candle.Interval, max(20, params.RSIPeriod+1))
+	history.SetAttributes(attribute.Int("candles", len(pastCandles)))
+	endSpan(history, historyErr)
+	if historyErr != nil {
+		log.Error("geçmiş veri çekilemedi", "err", historyErr)
 		return nil // Akışı bozma
 	}
 
@@ -54,69 +222,328 @@ func (s *TradingService) ProcessIncomingCandle(candle domain.Candle) error {
 	// İndikatör hesaplarken diziyi ters çevirmek en sağlıklısıdır.
 	reverseCandles(pastCandles)
 
+	// Cüzdanın güncel değerini (mark-to-market) metriğe yaz
+	s.updateEquity(candle.Close)
+
 	// Yeterli veri var mı?
-	if len(pastCandles) < 3 {
-		fmt.Println("⚠️ Strateji için yeterli veri yok, veri birikmesi bekleniyor...")
+	if len(pastCandles) < params.RSIPeriod+1 {
+		log.Info("strateji için yeterli veri yok, veri birikmesi bekleniyor", "candles", len(pastCandles))
 		return nil
 	}
 
+	// Şüpheli mum RSI penceresindeyse karar verilmez (saklandığı için geçmişte görünür).
+	if suspectOpen := s.suspectOpen.Load(); suspectOpen != 0 {
+		for _, c := range pastCandles[len(pastCandles)-params.RSIPeriod-1:] {
+			if c.OpenTime.UnixMicro() == suspectOpen {
+				log.Info("şüpheli mum RSI penceresinde, karar verilmiyor", "suspect_open_time", c.OpenTime)
+				return nil
+			}
+		}
+	}
+
 	// 3. İndikatörleri Hesapla
-	rsi := CalculateRSI(pastCandles, 3)
-	sma := CalculateSMA(pastCandles, 3)
+	_, indicators := startSpan(ctx, "indicators")
+	rsi := CalculateRSI(pastCandles, params.RSIPeriod)
+	sma := CalculateSMA(pastCandles, params.RSIPeriod)
+	indicators.SetAttributes(attribute.Float64("rsi", rsi), attribute.Float64("sma", sma))
+	indicators.End()
+
+	log.Info("analiz", "price", candle.Close, "rsi", rsi, "sma", sma)
 
-	fmt.Printf("📊 ANALİZ: %s | Fiyat: %.2f | RSI: %.2f | SMA: %.2f\n",
-		candle.Symbol, candle.Close, rsi, sma)
+	// Emir defteri varsa spread ve dengesizliği de hesaba katalım (şimdilik bilgi amaçlı).
+	bookInfo := ""
+	if s.orderBook != nil {
+		bid, ask, ok := s.orderBook.BestBidAsk(candle.Symbol)
+		imbalance, _ := s.orderBook.Imbalance(candle.Symbol, 10)
+		if ok {
+			bookInfo = fmt.Sprintf(" | Spread: %.2f | Dengesizlik: %+.2f", ask.Price-bid.Price, imbalance)
+			log.Debug("emir defteri", "bid", bid.Price, "ask", ask.Price, "spread", ask.Price-bid.Price, "imbalance", imbalance)
+		}
+	}
 
 	// 4. Karar Mekanizması (Basit Strateji)
-	// Kural: RSI 30'un altındaysa (Oversold) -> AL
-	// Kural: RSI 70'in üstündeyse (Overbought) -> SAT
+	// Kural: RSI Oversold'un (varsayılan 30) altındaysa -> AL
+	// Kural: RSI Overbought'un (varsayılan 70) üstündeyse -> SAT
+
+	signalCtx, decide := startSpan(ctx, "signal")
+	defer decide.End()
 
 	var signal domain.TradeSignal
 
-	if rsi < 30 {
+	if rsi < params.Oversold {
 		signal = domain.TradeSignal{
+			Strategy:  domain.StrategyRSI,
 			Symbol:    candle.Symbol,
 			Action:    domain.SignalBuy,
 			Price:     candle.Close,
-			Timestamp: candle.EventTime,
-			Reason:    fmt.Sprintf("RSI Aşırı Satım (%.2f < 30)", rsi),
+			Timestamp: candle.CloseTime,
+			Reason:    fmt.Sprintf("RSI Aşırı Satım (%.2f < %g)%s", rsi, params.Oversold, bookInfo),
+
+			CorrelationID: candle.CorrelationID,
 		}
-	} else if rsi > 70 {
+	} else if rsi > params.Overbought {
 		signal = domain.TradeSignal{
+			Strategy:  domain.StrategyRSI,
 			Symbol:    candle.Symbol,
 			Action:    domain.SignalSell,
 			Price:     candle.Close,
-			Timestamp: candle.EventTime,
-			Reason:    fmt.Sprintf("RSI Aşırı Alım (%.2f > 70)", rsi),
+			Timestamp: candle.CloseTime,
+			Reason:    fmt.Sprintf("RSI Aşırı Alım (%.2f > %g)%s", rsi, params.Overbought, bookInfo),
+
+			CorrelationID: candle.CorrelationID,
 		}
 	}
 
 	// 5. Eğer bir sinyal üretildiyse, bunu yayınla!
+	decide.SetAttributes(attribute.String("action", string(signal.Action)))
 	if signal.Action != "" {
-		fmt.Printf("🚨 SİNYAL ÜRETİLDİ: %s %s\n", signal.Action, signal.Reason)
-		_ = s.publisher.PublishSignal(signal)
+		log.Info("sinyal üretildi", "action", signal.Action, "price", signal.Price, "reason", signal.Reason)
+		if s.replay {
+			return nil
+		}
+		s.metrics.IncSignal(signal.Strategy, signal.Action)
+
+		_, publish := startSpan(signalCtx, "publish", attribute.String("channel", "signals"))
+		publishErr := s.publisher.PublishSignal(signal)
+		endSpan(publish, publishErr)
+		if publishErr != nil {
+			log.Warn("sinyal yayınlanamadı", "err", publishErr)
+		} else {
+			log.Debug("sinyal yayınlandı")
+		}
 		// İleride buraya: s.exchange.ExecuteOrder(signal) gelecek (Paper Trading)
-		s.ExecutePaperTrade(signal)
+		// Yetersiz bakiye ve kayıt hataları ExecutePaperTrade içinde loglanır.
+		_, _ = s.ExecutePaperTrade(signalCtx, signal)
 	}
 
 	return nil
 }
 
+// isStrategySeries: Mum stratejinin karar verdiği seriye (borsa, sembol, interval) mi ait?
+func (s *TradingService) isStrategySeries(candle domain.Candle) bool {
+	return candle.Exchange == s.strategyExchange && candle.Symbol == s.strategySymbol &&
+		candle.Interval == s.strategyInterval
+}
+
+// ProcessPartialCandle: Henüz kapanmamış mumu saklar ve canlı kanala yayınlar.
+// Strateji burada çalışmaz; kararlar sadece kesinleşmiş mumlarla verilir.
+func (s *TradingService) ProcessPartialCandle(ctx context.Context, candle domain.Candle) error {
+	ctx, span := startSpan(ctx, "ProcessPartialCandle",
+		attribute.String("exchange", candle.Exchange),
+		attribute.String("symbol", candle.Symbol),
+		attribute.String("cid", candle.CorrelationID))
+	defer span.End()
+
+	if s.validator != nil {
+		if severity, reasons := s.validator.CheckPartial(candle); severity != "" {
+			// Kapanmamış mum birkaç saniye sonra tekrar gelir; karantinayı doldurmasın, sadece logla.
+			s.log.Warn("canlı mum reddedildi", "cid", candle.CorrelationID, "exchange", candle.Exchange,
+				"symbol", candle.Symbol, "reasons", reasons)
+			return nil
+		}
+	}
+	_, save := startSpan(ctx, "save", attribute.String("db.operation", "save_partial"))
+	err := s.repo.SavePartial(candle)
+	endSpan(save, err)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("canlı mum kayıt hatası: %v", err)
+	}
+	_ = s.publisher.PublishPartialCandle(candle)
+
+	for _, observer := range s.observers {
+		observer.OnCandle(candle)
+	}
+	return nil
+}
+
+// quarantineCandle: Şüpheli/bozuk mumu loglar ve karantina tablosuna yazar.
+func (s *TradingService) quarantineCandle(log *slog.Logger, candle domain.Candle, severity domain.QualitySeverity, reasons []string) {
+	log.Warn("mum karantinaya alındı", "severity", severity, "open_time", candle.OpenTime, "reasons", reasons)
+
+	if s.quarantine == nil {
+		return
+	}
+	err := s.quarantine.SaveQuarantined(domain.QuarantinedCandle{
+		Candle:     candle,
+		Severity:   severity,
+		Reasons:    reasons,
+		DetectedAt: time.Now(),
+	})
+	if err != nil {
+		log.Error("karantina kaydı başarısız", "err", err)
+	}
+}
+
+// saveWallet: İşlem sonrası bakiyeyi kaydeder.
+func (s *TradingService) saveWallet(ctx context.Context, log *slog.Logger, wallet domain.Wallet) error {
+	_, span := startSpan(ctx, "wallet update", attribute.String("db.operation", "update_wallet"))
+	err := s.walletRepo.UpdateWallet(wallet)
+	endSpan(span, err)
+	if err != nil {
+		log.Error("cüzdan kaydedilemedi", "err", err)
+	}
+	return err
+}
+
+// updateEquity: Cüzdanın verilen fiyatla USDT karşılığını metriğe yazar.
+func (s *TradingService) updateEquity(price float64) {
+	wallet, err := s.walletRepo.GetWallet()
+	if err != nil {
+		return
+	}
+	s.metrics.SetWalletEquity(wallet.USDTBalance + wallet.CoinBalance*price)
+}
+
+// paperStartingUSDT: Sıfırlanan cüzdanın başlangıç bakiyesi (wallets tablosundaki 'demo' ile aynı).
+const paperStartingUSDT = 1000.0
+
+// StartStrategy: ports.StrategyController
+func (s *TradingService) StartStrategy() {
+	if s.strategyStopped.Swap(false) {
+		s.log.Info("strateji başlatıldı")
+	}
+}
+
+// StopStrategy: ports.StrategyController
+func (s *TradingService) StopStrategy() {
+	if !s.strategyStopped.Swap(true) {
+		s.log.Info("strateji durduruldu")
+	}
+}
+
+// StrategyRunning: ports.StrategyController
+func (s *TradingService) StrategyRunning() bool {
+	return !s.strategyStopped.Load()
+}
+
+// GetWallet: ports.StrategyController
+func (s *TradingService) GetWallet() (*domain.Wallet, error) {
+	return s.walletRepo.GetWallet()
+}
+
+// ResetWallet: ports.StrategyController. Açık pozisyon (coin) silinir, bakiye başlangıca döner.
+func (s *TradingService) ResetWallet(ctx context.Context) (_ *domain.Wallet, err error) {
+	_, span := startSpan(ctx, "wallet reset")
+	defer func() { endSpan(span, err) }()
+
+	s.walletMu.Lock()
+	defer s.walletMu.Unlock()
+
+	wallet, err := s.walletRepo.GetWallet()
+	if err != nil {
+		return nil, err
+	}
+	wallet.USDTBalance = paperStartingUSDT
+	wallet.CoinBalance = 0
+	if err := s.walletRepo.UpdateWallet(*wallet); err != nil {
+		return nil, err
+	}
+	s.log.Info("cüzdan sıfırlandı", "account", wallet.ID, "usdt", wallet.USDTBalance)
+	s.metrics.SetWalletEquity(wallet.USDTBalance)
+
+	update := domain.WalletUpdate{Account: wallet.ID, USDT: wallet.USDTBalance, BTC: wallet.CoinBalance}
+	if err := s.publisher.PublishWallet(update); err != nil {
+		s.log.Warn("cüzdan yayını başarısız", "err", err)
+	}
+	return wallet, nil
+}
+
+// StrategyParams: ports.StrategyController
+func (s *TradingService) StrategyParams() domain.StrategyParams {
+	return *s.params.Load()
+}
+
+// UpdateStrategyParams: ports.StrategyController
+func (s *TradingService) UpdateStrategyParams(patch domain.StrategyParamsPatch) (domain.StrategyParams, error) {
+	for {
+		current := s.params.Load()
+		params := patch.Apply(*current)
+		if err := params.Validate(); err != nil {
+			return *current, err
+		}
+		// Arada başka bir güncelleme olduysa onun üzerine tekrar birleştir.
+		if s.params.CompareAndSwap(current, &params) {
+			s.log.Info("strateji ayarları değişti", "rsi_period", params.RSIPeriod,
+				"oversold", params.Oversold, "overbought", params.Overbought)
+			return params, nil
+		}
+	}
+}
+
+// PlaceManualOrder: ports.StrategyController. Emir, strateji borsasındaki son 1m mumun
+// kapanış fiyatından gerçekleşir ve "signals:manual" kanalına yayınlanır.
+func (s *TradingService) PlaceManualOrder(ctx context.Context, order domain.ManualOrder) (_ *domain.Wallet, err error) {
+	ctx, span := startSpan(ctx, "manual order",
+		attribute.String("symbol", order.Symbol),
+		attribute.String("action", string(order.Side)))
+	defer func() { endSpan(span, err) }()
+
+	// Cüzdan tek bir coin bakiyesi tutar; başka sembolün alımı bu bakiyeye eklenirdi.
+	if order.Symbol != s.strategySymbol {
+		return nil, fmt.Errorf("%w: %s (cüzdan: %s)", domain.ErrSymbolNotTraded, order.Symbol, s.strategySymbol)
+	}
+
+	latest, err := s.repo.GetLatestCandles(s.strategyExchange, order.Symbol, "1m", 1)
+	if err != nil {
+		return nil, fmt.Errorf("son fiyat okunamadı: %w", err)
+	}
+	if len(latest) == 0 {
+		return nil, domain.ErrNoPrice
+	}
+
+	signal := domain.TradeSignal{
+		Strategy:  domain.StrategyManual,
+		Symbol:    order.Symbol,
+		Action:    order.Side,
+		Price:     latest[0].Close,
+		Timestamp: time.Now(),
+		Reason:    fmt.Sprintf("Manuel emir (%s)", order.User),
+
+		CorrelationID: domain.NewCorrelationID(),
+	}
+	wallet, err := s.ExecutePaperTrade(ctx, signal)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Info("manuel emir gerçekleşti", "cid", signal.CorrelationID, "user", order.User,
+		"symbol", order.Symbol, "action", order.Side, "price", signal.Price)
+
+	s.metrics.IncSignal(signal.Strategy, signal.Action)
+	if err := s.publisher.PublishSignal(signal); err != nil {
+		s.log.Warn("sinyal yayınlanamadı", "cid", signal.CorrelationID, "err", err)
+	}
+	return wallet, nil
+}
+
 // Yardımcı Fonksiyon: Slice'ı ters çevirir
 func reverseCandles(candles []domain.Candle) {
 	for i, j := 0, len(candles)-1; i < j; i, j = i+1, j-1 {
 		candles[i], candles[j] = candles[j], candles[i]
 	}
 }
-func (s *TradingService) ExecutePaperTrade(signal domain.TradeSignal) {
+
+// ExecutePaperTrade: Sinyali paper trading cüzdanında tüm bakiyeyle gerçekleştirir.
+// Alınacak/satılacak bakiye yoksa domain.ErrInsufficientBalance döner.
+func (s *TradingService) ExecutePaperTrade(ctx context.Context, signal domain.TradeSignal) (*domain.Wallet, error) {
+	log := s.log.With("cid", signal.CorrelationID, "symbol", signal.Symbol, "action", signal.Action)
+	ctx, span := startSpan(ctx, "execute", attribute.String("action", string(signal.Action)))
+	defer span.End()
+
+	// Okuma ile kayıt arasında başka bir işlem cüzdanı değiştiremez.
+	s.walletMu.Lock()
+	defer s.walletMu.Unlock()
+
 	// 1. Cüzdanı getir
+	_, load := startSpan(ctx, "wallet load", attribute.String("db.operation", "get_wallet"))
 	wallet, err := s.walletRepo.GetWallet()
+	endSpan(load, err)
 	if err != nil {
-		fmt.Printf("Cüzdan hatası : %v\n", err)
-		return // Hata varsa devam etme
+		log.Error("cüzdan okunamadı", "err", err)
+		return nil, err // Hata varsa devam etme
 	}
 
-	fmt.Printf("Cüzdan öncesi: %.2f USDT | %.5f BTC\n", wallet.USDTBalance, wallet.CoinBalance)
+	log.Debug("cüzdan (işlem öncesi)", "usdt", wallet.USDTBalance, "coin", wallet.CoinBalance)
 
 	// Değişiklik oldu mu diye kontrol etmek için bayrak
 	tradeHappened := false
@@ -129,13 +556,16 @@ func (s *TradingService) ExecutePaperTrade(signal domain.TradeSignal) {
 			wallet.CoinBalance += amountToBuy
 			wallet.USDTBalance = 0 // hepsini harcadık
 
-			fmt.Printf("🟢 Alım yapıldı %.5f BTC alındı (Fiyat : %.2f)\n", amountToBuy, signal.Price)
+			log.Info("alım yapıldı", "amount", amountToBuy, "price", signal.Price)
 
 			// Veritabanını güncelle
-			s.walletRepo.UpdateWallet(*wallet)
+			if err := s.saveWallet(ctx, log, *wallet); err != nil {
+				return nil, err
+			}
 			tradeHappened = true
 		} else {
-			fmt.Println("!! Yetersiz bakiye (usdt)")
+			log.Info("yetersiz bakiye (usdt)", "usdt", wallet.USDTBalance)
+			return wallet, domain.ErrInsufficientBalance
 		}
 
 	} else if signal.Action == domain.SignalSell {
@@ -145,31 +575,44 @@ func (s *TradingService) ExecutePaperTrade(signal domain.TradeSignal) {
 			wallet.USDTBalance += amountUsdt
 			wallet.CoinBalance = 0
 
-			fmt.Printf("🔴 Satış Yapıldı: %.2f USDT kazanıldı Fiyat: %.2f\n", amountUsdt, signal.Price)
+			log.Info("satış yapıldı", "usdt", amountUsdt, "price", signal.Price)
 
 			// Veritabanını güncelle
-			s.walletRepo.UpdateWallet(*wallet)
+			if err := s.saveWallet(ctx, log, *wallet); err != nil {
+				return nil, err
+			}
 			tradeHappened = true
 		} else {
-			fmt.Println("!! Satılacak coin yok")
+			log.Info("satılacak coin yok", "coin", wallet.CoinBalance)
+			return wallet, domain.ErrInsufficientBalance
 		}
 	}
 
-	fmt.Printf("Cüzdan sonrası: %.2f USDT | %.5f BTC\n", wallet.USDTBalance, wallet.CoinBalance)
+	log.Debug("cüzdan (işlem sonrası)", "usdt", wallet.USDTBalance, "coin", wallet.CoinBalance)
 
 	// 👇 KRİTİK EKLEME BURASI ŞEF 👇
 	// Eğer işlem gerçekleştiyse, yeni bakiyeyi WebSocket'ten gönder
 	if tradeHappened {
+		s.metrics.IncPaperTrade(signal.Action)
+		s.metrics.SetWalletEquity(wallet.USDTBalance + wallet.CoinBalance*signal.Price)
+
 		update := domain.WalletUpdate{
-			USDT: wallet.USDTBalance,
-			BTC:  wallet.CoinBalance,
+			Account: wallet.ID,
+			USDT:    wallet.USDTBalance,
+			BTC:     wallet.CoinBalance,
+
+			CorrelationID: signal.CorrelationID,
 		}
 
 		// Publisher üzerinden React'a fırlatıyoruz
-		if err := s.publisher.PublishWallet(update); err != nil {
-			fmt.Printf("⚠️ Cüzdan yayını başarısız: %v\n", err)
+		_, publish := startSpan(ctx, "publish", attribute.String("channel", "wallet"))
+		err := s.publisher.PublishWallet(update)
+		endSpan(publish, err)
+		if err != nil {
+			log.Warn("cüzdan yayını başarısız", "err", err)
 		} else {
-			fmt.Println("📡 Cüzdan güncellendi ve frontend'e gönderildi.")
+			log.Debug("cüzdan güncellendi ve frontend'e gönderildi")
 		}
 	}
+	return wallet, nil
 }
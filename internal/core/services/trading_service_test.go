@@ -0,0 +1,201 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+)
+
+// memCandles: Saklanan mumları bellekte tutan CandleRepository.
+type memCandles struct {
+	mu      sync.Mutex
+	candles []domain.Candle
+}
+
+func (m *memCandles) Save(c domain.Candle) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.candles = append(m.candles, c)
+	return nil
+}
+
+func (m *memCandles) SavePartial(domain.Candle) error { return nil }
+
+func (m *memCandles) GetLatestCandles(exchange, symbol, interval string, limit int) ([]domain.Candle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []domain.Candle
+	for _, c := range slices.Backward(m.candles) {
+		if c.Exchange == exchange && c.Symbol == symbol && c.Interval == interval && len(out) < limit {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+type memWallet struct {
+	mu     sync.Mutex
+	wallet domain.Wallet
+}
+
+func (w *memWallet) GetWallet() (*domain.Wallet, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	wallet := w.wallet
+	return &wallet, nil
+}
+
+func (w *memWallet) UpdateWallet(wallet domain.Wallet) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.wallet = wallet
+	return nil
+}
+
+// recordingBus: Yayınlanan sinyal, cüzdan, index, feed uyarısı ve arbitraj fırsatlarını biriktirir.
+type recordingBus struct {
+	ports.EventBus
+	mu      sync.Mutex
+	signals []domain.TradeSignal
+	wallets []domain.WalletUpdate
+	indexes []domain.IndexPrice
+	alerts  []domain.FeedAlert
+	arbs    []domain.ArbitrageOpportunity
+}
+
+func (b *recordingBus) PublishCandle(domain.Candle) error { return nil }
+
+func (b *recordingBus) PublishSignal(signal domain.TradeSignal) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.signals = append(b.signals, signal)
+	return nil
+}
+
+func (b *recordingBus) PublishWallet(update domain.WalletUpdate) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.wallets = append(b.wallets, update)
+	return nil
+}
+
+func (b *recordingBus) PublishIndex(index domain.IndexPrice) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.indexes = append(b.indexes, index)
+	return nil
+}
+
+func (b *recordingBus) PublishFeedAlert(alert domain.FeedAlert) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.alerts = append(b.alerts, alert)
+	return nil
+}
+
+func (b *recordingBus) PublishArbitrage(opportunity domain.ArbitrageOpportunity) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.arbs = append(b.arbs, opportunity)
+	return nil
+}
+
+func (b *recordingBus) signalCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.signals)
+}
+
+func newTestTradingService() (*TradingService, *memCandles, *memWallet, *recordingBus) {
+	candles := &memCandles{}
+	wallet := &memWallet{wallet: domain.Wallet{ID: "demo", USDTBalance: paperStartingUSDT}}
+	bus := &recordingBus{}
+	return NewTradingService(candles, wallet, bus), candles, wallet, bus
+}
+
+func TestFlaggedCandleSuppressesStrategyUntilOutOfRSIWindow(t *testing.T) {
+	s, candles, _, bus := newTestTradingService()
+	s.SetValidator(NewCandleValidator(ValidatorConfig{JumpSigma: 8, Window: 20, MinHistory: 5}), nil)
+
+	// Sürekli düşen fiyat: RSI 0, her mum AL sinyali üretir.
+	price := 100.0
+	minute := 0
+	feed := func(step float64) int {
+		t.Helper()
+		price -= step
+		before := bus.signalCount()
+		if err := s.ProcessIncomingCandle(t.Context(), vCandle(minute, price)); err != nil {
+			t.Fatal(err)
+		}
+		minute++
+		return bus.signalCount() - before
+	}
+	for i := range 8 {
+		feed(0.1 + 0.05*float64(i%2))
+	}
+	if bus.signalCount() == 0 {
+		t.Fatal("düşen fiyatta AL sinyali bekleniyordu")
+	}
+
+	if n := feed(5); n != 0 {
+		t.Fatal("işaretlenen mum karar üretmemeli")
+	}
+	if len(candles.candles) != 9 {
+		t.Fatalf("işaretlenen mum yine de saklanmalı, %d mum", len(candles.candles))
+	}
+	period := s.StrategyParams().RSIPeriod
+	for i := range period {
+		if n := feed(0.1); n != 0 {
+			t.Fatalf("şüpheli mumdan sonraki %d. mum RSI penceresinde iken sinyal üretti", i+1)
+		}
+	}
+	if n := feed(0.1); n != 1 {
+		t.Fatal("şüpheli mum pencereden çıkınca strateji devam etmeli")
+	}
+}
+
+func TestUpdateStrategyParamsConcurrentPatchesMerge(t *testing.T) {
+	s, _, _, _ := newTestTradingService()
+	period, oversold := 14, 25.0
+
+	var wg sync.WaitGroup
+	wg.Go(func() { s.UpdateStrategyParams(domain.StrategyParamsPatch{RSIPeriod: &period}) })
+	wg.Go(func() { s.UpdateStrategyParams(domain.StrategyParamsPatch{Oversold: &oversold}) })
+	wg.Wait()
+
+	want := domain.StrategyParams{RSIPeriod: 14, Oversold: 25, Overbought: domain.DefaultStrategyParams.Overbought}
+	if got := s.StrategyParams(); got != want {
+		t.Fatalf("iki kısmi güncelleme de korunmalı: %+v", got)
+	}
+
+	invalid := 90.0
+	if _, err := s.UpdateStrategyParams(domain.StrategyParamsPatch{Oversold: &invalid}); err == nil {
+		t.Fatal("overbought'tan büyük oversold reddedilmeli")
+	}
+	if got := s.StrategyParams(); got != want {
+		t.Fatalf("reddedilen güncelleme ayarları değiştirmemeli: %+v", got)
+	}
+}
+
+func TestPlaceManualOrderRejectsOtherSymbols(t *testing.T) {
+	s, candles, wallet, _ := newTestTradingService()
+	candles.Save(vCandle(0, 100))
+	eth := vCandle(0, 3000)
+	eth.Symbol = "ETHUSDT"
+	candles.Save(eth)
+
+	_, err := s.PlaceManualOrder(t.Context(), domain.ManualOrder{Symbol: "ETHUSDT", Side: domain.SignalBuy, User: "test"})
+	if !errors.Is(err, domain.ErrSymbolNotTraded) {
+		t.Fatalf("ETHUSDT emri reddedilmeli: %v", err)
+	}
+	if w, _ := wallet.GetWallet(); w.USDTBalance != paperStartingUSDT || w.CoinBalance != 0 {
+		t.Fatalf("reddedilen emir cüzdana dokunmamalı: %+v", w)
+	}
+
+	if _, err := s.PlaceManualOrder(t.Context(), domain.ManualOrder{Symbol: "BTCUSDT", Side: domain.SignalBuy, User: "test"}); err != nil {
+		t.Fatalf("strateji sembolü kabul edilmeli: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// ValidatorConfig: Mum doğrulama eşikleri.
+type ValidatorConfig struct {
+	// JumpSigma: Kapanışın bir önceki kapanışa göre getirisi, son getirilerin standart sapmasının
+	// bu katından büyükse mum "ani sıçrama" olarak işaretlenir. 0 ise kontrol kapalı.
+	JumpSigma float64
+	// Window: Volatilite için tutulan son getiri sayısı.
+	Window int
+	// MinHistory: Sıçrama kontrolü için gereken en az getiri sayısı (başlangıçta yanlış alarm olmasın).
+	MinHistory int
+}
+
+// CandleValidator: Kesinleşmiş mumları saklanmadan önce kontrol eder.
+// Seri (borsa/sembol/interval) başına son mumu ve getirileri tutar; goroutine-safe'dir.
+type CandleValidator struct {
+	cfg ValidatorConfig
+
+	mu     sync.Mutex
+	series map[string]*seriesState
+}
+
+type seriesState struct {
+	last    domain.Candle
+	returns []float64 // Son Window adet log getiri (halka tampon gibi kullanılır)
+	next    int
+}
+
+func NewCandleValidator(cfg ValidatorConfig) *CandleValidator {
+	if cfg.Window <= 1 {
+		cfg.Window = 30
+	}
+	if cfg.MinHistory <= 1 {
+		cfg.MinHistory = 10
+	}
+	return &CandleValidator{cfg: cfg, series: make(map[string]*seriesState)}
+}
+
+// CheckPartial: Kapanmamış mum için sadece yapısal kontroller (seri durumu değişmez).
+func (v *CandleValidator) CheckPartial(c domain.Candle) (domain.QualitySeverity, []string) {
+	if reasons := structuralIssues(c); len(reasons) > 0 {
+		return domain.QualityRejected, reasons
+	}
+	return "", nil
+}
+
+// Check: Kesinleşmiş mumu kontrol eder. Sorun yoksa severity boş döner.
+// Reddedilen mumlar seri durumunu değiştirmez; işaretlenenler (saklandıkları için) değiştirir.
+func (v *CandleValidator) Check(c domain.Candle) (domain.QualitySeverity, []string) {
+	if reasons := structuralIssues(c); len(reasons) > 0 {
+		return domain.QualityRejected, reasons
+	}
+
+	key := c.Exchange + "|" + c.Symbol + "|" + c.Interval
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	st := v.series[key]
+	if st == nil {
+		v.series[key] = &seriesState{last: c}
+		return "", nil
+	}
+
+	switch {
+	case c.OpenTime.Before(st.last.OpenTime):
+		return domain.QualityRejected, []string{fmt.Sprintf("zaman geriye gitti (son: %s, gelen: %s)",
+			st.last.OpenTime.UTC().Format("15:04:05"), c.OpenTime.UTC().Format("15:04:05"))}
+
+	case c.OpenTime.Equal(st.last.OpenTime):
+		// Aynı mum tekrar geldi: birebir aynıysa gereksiz, farklıysa borsa düzeltmesi (upsert edilir).
+		if sameCandle(c, st.last) {
+			return domain.QualityRejected, []string{"tekrarlanan mum"}
+		}
+		st.last = c
+		return domain.QualityFlagged, []string{"aynı zamanlı mum farklı değerlerle tekrar geldi (düzeltme)"}
+	}
+
+	ret := math.Log(c.Close / st.last.Close)
+	var reasons []string
+	if v.cfg.JumpSigma > 0 && len(st.returns) >= v.cfg.MinHistory {
+		if sigma := stddev(st.returns); sigma > 0 && math.Abs(ret) > v.cfg.JumpSigma*sigma {
+			reasons = append(reasons, fmt.Sprintf("ani fiyat sıçraması (%.1f sigma, %%%.2f)",
+				math.Abs(ret)/sigma, (math.Exp(ret)-1)*100))
+		}
+	}
+
+	st.last = c
+	if len(reasons) > 0 {
+		// Aykırı getiri volatiliteyi şişirip sonraki sıçramaları gizlemesin diye pencereye eklenmez.
+		return domain.QualityFlagged, reasons
+	}
+	if len(st.returns) < v.cfg.Window {
+		st.returns = append(st.returns, ret)
+	} else {
+		st.returns[st.next] = ret
+		st.next = (st.next + 1) % v.cfg.Window
+	}
+	return "", nil
+}
+
+// structuralIssues: Tek başına mumun içinde tutarsızlık var mı?
+func structuralIssues(c domain.Candle) []string {
+	var reasons []string
+	if c.Open <= 0 || c.High <= 0 || c.Low <= 0 || c.Close <= 0 {
+		reasons = append(reasons, "sıfır veya negatif fiyat")
+	}
+	if c.High < c.Low {
+		reasons = append(reasons, fmt.Sprintf("high < low (%.8g < %.8g)", c.High, c.Low))
+	}
+	if c.Close > c.High || c.Close < c.Low {
+		reasons = append(reasons, fmt.Sprintf("close [low, high] dışında (%.8g)", c.Close))
+	}
+	if c.Open > c.High || c.Open < c.Low {
+		reasons = append(reasons, fmt.Sprintf("open [low, high] dışında (%.8g)", c.Open))
+	}
+	if c.Volume < 0 {
+		reasons = append(reasons, "negatif hacim")
+	}
+	if c.OpenTime.IsZero() {
+		reasons = append(reasons, "açılış zamanı yok")
+	}
+	return reasons
+}
+
+func sameCandle(a, b domain.Candle) bool {
+	return a.Open == b.Open && a.High == b.High && a.Low == b.Low && a.Close == b.Close && a.Volume == b.Volume
+}
+
+func stddev(values []float64) float64 {
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	var sum float64
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// vCandle: minute. dakikada açılan, open = close olan geçerli mum.
+func vCandle(minute int, close float64) domain.Candle {
+	return domain.Candle{
+		Exchange: domain.ExchangeBinance,
+		Symbol:   "BTCUSDT",
+		Interval: "1m",
+		Open:     close,
+		High:     close,
+		Low:      close,
+		Close:    close,
+		Volume:   1,
+		OpenTime: barBase.Add(time.Duration(minute) * time.Minute),
+		IsClosed: true,
+	}
+}
+
+// quiet: minute 0'dan başlayıp ±%0.1 salınan n mum (küçük ama sıfır olmayan volatilite).
+func quiet(n int) []validatorStep {
+	steps := make([]validatorStep, n)
+	for i := range steps {
+		price := 100.0
+		if i%2 == 1 {
+			price = 100.1
+		}
+		steps[i] = validatorStep{candle: vCandle(i, price)}
+	}
+	return steps
+}
+
+type validatorStep struct {
+	candle   domain.Candle
+	severity domain.QualitySeverity
+	reason   string // Nedenlerden birinde geçmesi gereken metin
+}
+
+func TestCandleValidatorCheck(t *testing.T) {
+	cfg := ValidatorConfig{JumpSigma: 8, Window: 20, MinHistory: 5}
+	modify := func(c domain.Candle, fn func(*domain.Candle)) domain.Candle {
+		fn(&c)
+		return c
+	}
+
+	tests := []struct {
+		name  string
+		steps []validatorStep
+	}{
+		{"sıfır fiyat", []validatorStep{
+			{candle: modify(vCandle(0, 100), func(c *domain.Candle) { c.Low = 0 }), severity: domain.QualityRejected, reason: "sıfır veya negatif"},
+		}},
+		{"high < low", []validatorStep{
+			{candle: modify(vCandle(0, 100), func(c *domain.Candle) { c.High, c.Low = 99, 101 }), severity: domain.QualityRejected, reason: "high < low"},
+		}},
+		{"close aralık dışında", []validatorStep{
+			{candle: modify(vCandle(0, 100), func(c *domain.Candle) { c.Close = 102 }), severity: domain.QualityRejected, reason: "close [low, high] dışında"},
+		}},
+		{"open aralık dışında", []validatorStep{
+			{candle: modify(vCandle(0, 100), func(c *domain.Candle) { c.Open = 98 }), severity: domain.QualityRejected, reason: "open [low, high] dışında"},
+		}},
+		{"negatif hacim", []validatorStep{
+			{candle: modify(vCandle(0, 100), func(c *domain.Candle) { c.Volume = -1 }), severity: domain.QualityRejected, reason: "negatif hacim"},
+		}},
+		{"açılış zamanı yok", []validatorStep{
+			{candle: modify(vCandle(0, 100), func(c *domain.Candle) { c.OpenTime = time.Time{} }), severity: domain.QualityRejected, reason: "açılış zamanı yok"},
+		}},
+		{"tekrar ve düzeltme", []validatorStep{
+			{candle: vCandle(0, 100)},
+			{candle: vCandle(0, 100), severity: domain.QualityRejected, reason: "tekrarlanan"},
+			{candle: vCandle(0, 100.5), severity: domain.QualityFlagged, reason: "düzeltme"},
+			// Düzeltilmiş hal artık son mumdur.
+			{candle: vCandle(0, 100.5), severity: domain.QualityRejected, reason: "tekrarlanan"},
+		}},
+		{"sırası bozuk mum", []validatorStep{
+			{candle: vCandle(5, 100)},
+			{candle: vCandle(4, 100), severity: domain.QualityRejected, reason: "zaman geriye gitti"},
+			// Reddedilen mum seri durumunu değiştirmez.
+			{candle: vCandle(6, 100.1)},
+		}},
+		{"MinHistory dolmadan sıçrama kontrol edilmez", append(quiet(3),
+			validatorStep{candle: vCandle(3, 110)},
+		)},
+		{"MinHistory sonrası sıçrama işaretlenir", append(quiet(7),
+			validatorStep{candle: vCandle(7, 105), severity: domain.QualityFlagged, reason: "ani fiyat sıçraması"},
+		)},
+		{"işaretlenen getiri pencereye eklenmez", append(quiet(7),
+			validatorStep{candle: vCandle(7, 105), severity: domain.QualityFlagged, reason: "sıçrama"},
+			// Önceki sıçrama pencereye girseydi sigma şişer, bu da geçerdi.
+			validatorStep{candle: vCandle(8, 110), severity: domain.QualityFlagged, reason: "sıçrama"},
+			validatorStep{candle: vCandle(9, 110.1)},
+		)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewCandleValidator(cfg)
+			for i, step := range tt.steps {
+				severity, reasons := v.Check(step.candle)
+				if severity != step.severity {
+					t.Fatalf("adım %d: severity %q, beklenen %q (%v)", i, severity, step.severity, reasons)
+				}
+				if step.reason != "" && !strings.Contains(strings.Join(reasons, "; "), step.reason) {
+					t.Fatalf("adım %d: nedenlerde %q yok: %v", i, step.reason, reasons)
+				}
+			}
+		})
+	}
+}
+
+func TestCandleValidatorCheckPartial(t *testing.T) {
+	v := NewCandleValidator(ValidatorConfig{})
+	if severity, _ := v.CheckPartial(vCandle(0, 100)); severity != "" {
+		t.Fatalf("geçerli canlı mum reddedildi: %q", severity)
+	}
+	bad := vCandle(0, 100)
+	bad.High = 90
+	if severity, _ := v.CheckPartial(bad); severity != domain.QualityRejected {
+		t.Fatalf("bozuk canlı mum reddedilmeli: %q", severity)
+	}
+	// Canlı mum seri durumunu değiştirmez: aynı kapanmış mum tekrar sayılmaz.
+	if severity, _ := v.Check(vCandle(0, 100)); severity != "" {
+		t.Fatalf("ilk kapanmış mum: %q", severity)
+	}
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+	"v2-trading-bot/internal/core/ports"
+)
+
+// FeedWatchdog: Her borsa/sembol/interval serisinin son kapanan mumunu izler.
+// Soket açık kaldığı halde mum gelmeyi keserse (Binance bunu yapabiliyor) uyarı yayınlar ve
+// bağlantıyı zorla yeniden kurdurur. ports.CandleObserver ve ports.FeedHealthProvider'ı implemente eder.
+type FeedWatchdog struct {
+	publisher   ports.EventBus
+	reconnector ports.FeedReconnector
+
+	// staleAfter: Beklenen mum aralığına ek olarak tanınan süre.
+	// 1m mum için son mumdan 1m + staleAfter sonra seri bayat sayılır.
+	staleAfter time.Duration
+	log        *slog.Logger
+
+	// intervals: İzlenen kline interval'ları (Expect ile). Boşsa süresi belli tüm interval'lar izlenir.
+	intervals []string
+
+	mu     sync.Mutex
+	series map[string]*feedState
+}
+
+type feedState struct {
+	health   domain.FeedHealth
+	interval time.Duration
+	attempts int // Bu bayatlama döneminde kaç kez uyarı/yeniden bağlanma yapıldı
+}
+
+func NewFeedWatchdog(publisher ports.EventBus, reconnector ports.FeedReconnector, staleAfter time.Duration) *FeedWatchdog {
+	if staleAfter <= 0 {
+		staleAfter = time.Minute
+	}
+	return &FeedWatchdog{
+		publisher:   publisher,
+		reconnector: reconnector,
+		staleAfter:  staleAfter,
+		log:         slog.Default(),
+		series:      make(map[string]*feedState),
+	}
+}
+
+// SetLogger: Servisin logger'ını ayarlar.
+func (w *FeedWatchdog) SetLogger(log *slog.Logger) {
+	w.log = log
+}
+
+// Expect: Borsa adaptörlerinin açtığı kline serilerini açılışta kaydeder ve izlemeyi bu
+// interval'larla sınırlar. Böylece hiç mum göndermeyen akış da açılıştan interval + staleAfter
+// sonra bayat sayılır. aggTrade'den üretilen barlar (agg30s, tick1000...) izlenmez; onların
+// sessizliği aggTrade akışının değil piyasanın durumunu gösterir.
+func (w *FeedWatchdog) Expect(exchanges, symbols, intervals []string) error {
+	now := time.Now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.intervals = intervals
+	for _, interval := range intervals {
+		if _, err := domain.IntervalDuration(interval); err != nil {
+			return err
+		}
+		for _, exchange := range exchanges {
+			for _, symbol := range symbols {
+				st := w.stateLocked(exchange, domain.NormalizeSymbol(symbol), interval)
+				st.health.LastReceived = now
+			}
+		}
+	}
+	return nil
+}
+
+// OnCandle: ports.CandleObserver (TradingService.AddMonitor ile, kayıttan önce çağrılır).
+// Sadece kapanmış ve Expect ile verilen interval'lardaki mumlar izlenir.
+func (w *FeedWatchdog) OnCandle(candle domain.Candle) {
+	if !candle.IsClosed || candle.Exchange == domain.ExchangeComposite {
+		return
+	}
+	if len(w.intervals) > 0 && !slices.Contains(w.intervals, candle.Interval) {
+		return
+	}
+	// Süresi belli olmayan barların (tick/volume) ne zaman geleceği bilinmez.
+	if _, err := domain.IntervalDuration(candle.Interval); err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	st := w.stateLocked(candle.Exchange, candle.Symbol, candle.Interval)
+	recovered := st.health.Stale
+	st.health.Stale = false
+	st.attempts = 0
+	st.health.LastReceived = time.Now()
+	if candle.OpenTime.After(st.health.LastCandle) {
+		st.health.LastCandle = candle.OpenTime
+	}
+	w.mu.Unlock()
+
+	if recovered {
+		w.alert(candle.Exchange, candle.Symbol, candle.Interval, "recovered", "veri akışı geri geldi")
+	}
+}
+
+// stateLocked: Serinin durumu; yoksa oluşturur. interval IntervalDuration'dan geçmiş olmalı.
+func (w *FeedWatchdog) stateLocked(exchange, symbol, interval string) *feedState {
+	key := exchange + "|" + symbol + "|" + interval
+	st := w.series[key]
+	if st == nil {
+		duration, _ := domain.IntervalDuration(interval)
+		st = &feedState{
+			health:   domain.FeedHealth{Exchange: exchange, Symbol: symbol, Interval: interval},
+			interval: duration,
+		}
+		w.series[key] = st
+	}
+	return st
+}
+
+// Run: Serileri periyodik kontrol eder. stop kapatılana kadar bloklar; goroutine içinde çağrılmalıdır.
+func (w *FeedWatchdog) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			w.check(now)
+		}
+	}
+}
+
+func (w *FeedWatchdog) check(now time.Time) {
+	type staleFeed struct {
+		health domain.FeedHealth
+		lag    time.Duration
+	}
+	var stale []staleFeed
+
+	w.mu.Lock()
+	for _, st := range w.series {
+		lag := now.Sub(st.health.LastReceived)
+		if lag <= st.interval+w.staleAfter {
+			continue
+		}
+		// Bayat kaldığı sürece her staleAfter'da bir tekrar dener (ilk denemede düzelmeyebilir).
+		if lag-st.interval-w.staleAfter < time.Duration(st.attempts)*w.staleAfter {
+			continue
+		}
+		if !st.health.Stale {
+			st.health.StaleCount++
+		}
+		st.health.Stale = true
+		st.attempts++
+		stale = append(stale, staleFeed{health: st.health, lag: lag})
+	}
+	w.mu.Unlock()
+
+	// Aynı borsa/sembolün birden fazla interval'ı bayatsa tek bir yeniden bağlanma yeter.
+	reconnected := make(map[string]bool)
+	for _, f := range stale {
+		h := f.health
+		w.alert(h.Exchange, h.Symbol, h.Interval, "stale",
+			fmt.Sprintf("%s boyunca mum gelmedi", f.lag.Round(time.Second)))
+
+		feed := h.Exchange + "|" + h.Symbol
+		if w.reconnector == nil || reconnected[feed] {
+			continue
+		}
+		reconnected[feed] = true
+		if n := w.reconnector.ReconnectFeed(h.Exchange, h.Symbol); n > 0 {
+			w.mu.Lock()
+			for _, st := range w.series {
+				if st.health.Exchange == h.Exchange && st.health.Symbol == h.Symbol {
+					st.health.Reconnects++
+				}
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
+func (w *FeedWatchdog) alert(exchange, symbol, interval, level, message string) {
+	log := w.log.With("exchange", exchange, "symbol", symbol, "interval", interval)
+	if level == "stale" {
+		log.Warn("veri akışı bayat", "detail", message)
+	} else {
+		log.Info(message, "level", level)
+	}
+
+	if w.publisher == nil {
+		return
+	}
+	err := w.publisher.PublishFeedAlert(domain.FeedAlert{
+		Exchange: exchange,
+		Symbol:   symbol,
+		Interval: interval,
+		Level:    level,
+		Message:  message,
+		Time:     time.Now(),
+	})
+	if err != nil {
+		log.Warn("feed uyarısı yayınlanamadı", "err", err)
+	}
+}
+
+// FeedHealth: ports.FeedHealthProvider
+func (w *FeedWatchdog) FeedHealth() []domain.FeedHealth {
+	now := time.Now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	out := make([]domain.FeedHealth, 0, len(w.series))
+	for _, st := range w.series {
+		h := st.health
+		h.LagSeconds = now.Sub(h.LastReceived).Seconds()
+		out = append(out, h)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Exchange != b.Exchange {
+			return a.Exchange < b.Exchange
+		}
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.Interval < b.Interval
+	})
+	return out
+}
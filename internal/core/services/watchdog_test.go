@@ -0,0 +1,121 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"v2-trading-bot/internal/core/domain"
+)
+
+// countingReconnector: ReconnectFeed çağrılarını sayar.
+type countingReconnector struct {
+	calls map[string]int
+}
+
+func (r *countingReconnector) ReconnectFeed(exchange, symbol string) int {
+	r.calls[exchange+"|"+symbol]++
+	return 1
+}
+
+func newTestWatchdog(t *testing.T) (*FeedWatchdog, *recordingBus, *countingReconnector, time.Time) {
+	t.Helper()
+	bus := &recordingBus{}
+	reconnector := &countingReconnector{calls: make(map[string]int)}
+	w := NewFeedWatchdog(bus, reconnector, time.Minute)
+	start := time.Now()
+	if err := w.Expect([]string{"binance", "okx"}, []string{"btcusdt"}, []string{"1m"}); err != nil {
+		t.Fatal(err)
+	}
+	return w, bus, reconnector, start
+}
+
+// alertLevels: Yayınlanan uyarıların borsa ve seviyeleri ("okx:stale").
+func alertLevels(bus *recordingBus) []string {
+	var out []string
+	for _, a := range bus.alerts {
+		out = append(out, a.Exchange+":"+a.Level)
+	}
+	return out
+}
+
+func TestFeedWatchdogExpectedSeriesGoStale(t *testing.T) {
+	w, bus, reconnector, start := newTestWatchdog(t)
+
+	if got := len(w.FeedHealth()); got != 2 {
+		t.Fatalf("açılışta 2 seri kayıtlı olmalı, %d", got)
+	}
+	// binance 1 dakika sonra mum gönderdi (OnCandle duvar saatini kullanır, zamanı elle kaydırıyoruz).
+	w.OnCandle(exchangeCandle("binance", 0, 100, 1))
+	w.mu.Lock()
+	w.series["binance|BTCUSDT|1m"].health.LastReceived = start.Add(time.Minute)
+	w.mu.Unlock()
+
+	// Eşik: interval (1m) + staleAfter (1m).
+	w.check(start.Add(2*time.Minute - time.Second))
+	if len(bus.alerts) != 0 {
+		t.Fatalf("eşik dolmadan uyarı: %v", alertLevels(bus))
+	}
+	// Hiç mum göndermeyen okx da bayat sayılır.
+	w.check(start.Add(2*time.Minute + time.Second))
+	if got := alertLevels(bus); len(got) != 1 || got[0] != "okx:stale" {
+		t.Fatalf("sadece okx bayat olmalı: %v", got)
+	}
+	if reconnector.calls["okx|BTCUSDT"] != 1 {
+		t.Fatalf("okx yeniden bağlanmalı: %v", reconnector.calls)
+	}
+}
+
+func TestFeedWatchdogRetryBackoffAndRecovery(t *testing.T) {
+	w, bus, reconnector, start := newTestWatchdog(t)
+	stale := start.Add(2*time.Minute + time.Second)
+
+	w.check(stale)
+	if reconnector.calls["binance|BTCUSDT"] != 1 {
+		t.Fatalf("ilk deneme: %v", reconnector.calls)
+	}
+	// Sonraki deneme staleAfter sonra; arada tekrar denenmez.
+	w.check(stale.Add(30 * time.Second))
+	if reconnector.calls["binance|BTCUSDT"] != 1 {
+		t.Fatalf("staleAfter dolmadan tekrar denendi: %v", reconnector.calls)
+	}
+	w.check(stale.Add(time.Minute))
+	if reconnector.calls["binance|BTCUSDT"] != 2 {
+		t.Fatalf("ikinci deneme bekleniyordu: %v", reconnector.calls)
+	}
+
+	var health domain.FeedHealth
+	for _, h := range w.FeedHealth() {
+		if h.Exchange == "binance" {
+			health = h
+		}
+	}
+	if !health.Stale || health.StaleCount != 1 || health.Reconnects != 2 {
+		t.Fatalf("aynı bayatlama dönemi tek sayılmalı: %+v", health)
+	}
+
+	// Mum geldi: seri düzelir, sayaç sıfırlanır.
+	bus.alerts = nil
+	w.OnCandle(exchangeCandle("binance", 5, 100, 1))
+	if got := alertLevels(bus); len(got) != 1 || got[0] != "binance:recovered" {
+		t.Fatalf("recovered uyarısı bekleniyordu: %v", got)
+	}
+	w.mu.Lock()
+	st := w.series["binance|BTCUSDT|1m"]
+	attempts, isStale := st.attempts, st.health.Stale
+	w.mu.Unlock()
+	if attempts != 0 || isStale {
+		t.Fatalf("düzelen seri sıfırlanmalı: attempts=%d stale=%v", attempts, isStale)
+	}
+}
+
+func TestFeedWatchdogIgnoresTradeBars(t *testing.T) {
+	w, _, _, _ := newTestWatchdog(t)
+
+	bar := exchangeCandle("binance", 0, 100, 1)
+	bar.Interval = "30s" // Süresi belli ama Expect'te yok (aggTrade barı gibi)
+	w.OnCandle(bar)
+	bar.Interval = "agg30s"
+	w.OnCandle(bar)
+	if got := len(w.FeedHealth()); got != 2 {
+		t.Fatalf("sadece kline serileri izlenmeli, %d seri", got)
+	}
+}
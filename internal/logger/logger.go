@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"sync/atomic"
+)
+
+// Options: Loglama ayarları.
+type Options struct {
+	// Level: Bileşen için özel seviye verilmemişse kullanılan seviye.
+	Level slog.Level
+	// JSON: true ise her satır bir JSON objesi olarak yazılır (log toplayıcılar için), false ise key=value.
+	JSON bool
+	// Components: Bileşen bazında seviye (Örn: "stream" -> debug, "depth" -> warn).
+	Components map[string]slog.Level
+	// Output: Boşsa os.Stderr.
+	Output io.Writer
+}
+
+// state: Init ile kurulan aktif ayarlar. For ile alınan loggerlar her kayıtta bunu okur,
+// böylece Init'ten önce (örn. paket seviyesinde) oluşturulan loggerlar da ayarlara uyar.
+type state struct {
+	handler    slog.Handler
+	level      slog.Level
+	components map[string]slog.Level
+}
+
+var current atomic.Pointer[state]
+
+func init() {
+	current.Store(&state{
+		handler: slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		level:   slog.LevelInfo,
+	})
+}
+
+// Init: Ayarları uygular ve slog.Default'u da (dolayısıyla standart log paketini) buraya yönlendirir.
+// main'in en başında çağrılmalıdır.
+func Init(opts Options) {
+	out := opts.Output
+	if out == nil {
+		out = os.Stderr
+	}
+	// Seviye filtresi bileşen bazında componentHandler'da yapılır; alttaki handler her şeyi yazar.
+	handlerOpts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var h slog.Handler
+	if opts.JSON {
+		h = slog.NewJSONHandler(out, handlerOpts)
+	} else {
+		h = slog.NewTextHandler(out, handlerOpts)
+	}
+
+	current.Store(&state{handler: h, level: opts.Level, components: opts.Components})
+	slog.SetDefault(For("app"))
+}
+
+// For: Bileşene ait logger'ı döner. Her kayda component=<isim> eklenir ve seviye
+// Options.Components'taki değere (yoksa Options.Level'e) göre filtrelenir.
+func For(component string) *slog.Logger {
+	return slog.New(&componentHandler{component: component})
+}
+
+// componentHandler: Aktif handler'ı her kayıtta current'tan çözer; With/WithGroup ile
+// eklenenleri sırasıyla tekrar uygular (sonuç state değişene kadar önbellekte tutulur).
+type componentHandler struct {
+	component string
+	ops       []func(slog.Handler) slog.Handler
+
+	resolved atomic.Pointer[resolvedHandler]
+}
+
+type resolvedHandler struct {
+	state   *state
+	handler slog.Handler
+}
+
+func (h *componentHandler) level(st *state) slog.Level {
+	if l, ok := st.components[h.component]; ok {
+		return l
+	}
+	return st.level
+}
+
+func (h *componentHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.level(current.Load())
+}
+
+func (h *componentHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler().Handle(ctx, r)
+}
+
+func (h *componentHandler) handler() slog.Handler {
+	st := current.Load()
+	if r := h.resolved.Load(); r != nil && r.state == st {
+		return r.handler
+	}
+	handler := st.handler.WithAttrs([]slog.Attr{slog.String("component", h.component)})
+	for _, op := range h.ops {
+		handler = op(handler)
+	}
+	h.resolved.Store(&resolvedHandler{state: st, handler: handler})
+	return handler
+}
+
+func (h *componentHandler) with(op func(slog.Handler) slog.Handler) *componentHandler {
+	ops := make([]func(slog.Handler) slog.Handler, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &componentHandler{component: h.component, ops: append(ops, op)}
+}
+
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(func(inner slog.Handler) slog.Handler { return inner.WithAttrs(attrs) })
+}
+
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(func(inner slog.Handler) slog.Handler { return inner.WithGroup(name) })
+}
@@ -1,5 +1,5 @@
 import React, { useEffect, useState, useRef } from 'react';
-import { Centrifuge } from 'centrifuge';
+import { Centrifuge, UnauthorizedError } from 'centrifuge';
 
 const TradingMonitor = () => {
     // --- STATE TANIMLARI ---
@@ -12,6 +12,10 @@ const TradingMonitor = () => {
     const [isConnected, setIsConnected] = useState(false);
     // Loglar
     const [logs, setLogs] = useState([]);
+    // Grafiği izleyen bağlantı sayısı (kline kanalının presence'ı)
+    const [viewers, setViewers] = useState(0);
+    // Strateji durumu (RPC: get_strategy). null ise bu bağlantıda strateji kontrolü yok.
+    const [strategy, setStrategy] = useState(null);
 
     const centrifugeRef = useRef(null);
 
@@ -34,10 +38,57 @@ const TradingMonitor = () => {
         }
     };
 
+    // RPC: Komutlar aynı bağlantı üzerinden gider. Yetki/parametre hataları loga düşer.
+    const call = async (method, data = {}) => {
+        try {
+            const res = await centrifugeRef.current.rpc(method, data);
+            return res.data;
+        } catch (err) {
+            addLog(`⛔ ${method}: ${err.message || err.code}`, 'error');
+            return null;
+        }
+    };
+
+    const toggleStrategy = async () => {
+        const res = await call(strategy?.running ? 'pause_strategy' : 'resume_strategy');
+        if (res) setStrategy(res);
+    };
+
+    const manualOrder = async (side) => {
+        const symbol = import.meta.env.VITE_SYMBOL || 'BTCUSDT';
+        const res = await call('place_manual_order', { symbol, side });
+        if (res) addLog(`🖐️ Manuel ${side} gerçekleşti`, side === 'BUY' ? 'buy' : 'sell');
+    };
+
     // --- WEBSOCKET BAĞLANTISI ---
     useEffect(() => {
-        // DİKKAT: Port 8085 (Backend'de ayarladığımız port)
-        const cent = new Centrifuge('ws://localhost:8085/connection/websocket', { 
+        // REST API ile aynı sunucu. WebSocket açılamazsa HTTP-streaming, o da olmazsa SSE denenir.
+        // Token: POST /api/v1/auth/login (veya `go run ./cmd/token`) ile alınıp web/.env.local'e yazılır.
+        const api = import.meta.env.VITE_API_URL || 'http://localhost:3000';
+        // Süresi dolmadan sunucu yeni token ister; son token ile POST /api/v1/auth/token çağrılır.
+        // Yeni token'daki rol bağlantı kopmadan geçerli olur. 401/403'te istemci yeniden denemez.
+        let token = import.meta.env.VITE_WS_TOKEN;
+        const getToken = async () => {
+            const res = await fetch(`${api}/api/v1/auth/token`, {
+                method: 'POST',
+                headers: { Authorization: `Bearer ${token}` },
+            });
+            if (res.status === 401 || res.status === 403) {
+                addLog('❌ Oturum süresi doldu, tekrar giriş yapın.', 'error');
+                throw new UnauthorizedError('oturum süresi doldu');
+            }
+            if (!res.ok) throw new Error(`token yenilenemedi: ${res.status}`);
+            token = (await res.json()).token;
+            return token;
+        };
+        const cent = new Centrifuge([
+            { transport: 'websocket', endpoint: `${api.replace(/^http/, 'ws')}/connection/websocket` },
+            { transport: 'http_stream', endpoint: `${api}/connection/http_stream` },
+            { transport: 'sse', endpoint: `${api}/connection/sse` },
+        ], {
+            emulationEndpoint: `${api}/emulation`,
+            token,
+            getToken,
             debug: false // Konsolu kirletmemesi için kapattım, hata ararken true yapabilirsin
         });
         
@@ -48,6 +99,8 @@ const TradingMonitor = () => {
             setStatus(`BAĞLANDI (Client ID: ${ctx.client})`);
             setIsConnected(true);
             addLog('✅ Sunucu bağlantısı başarılı.', 'success');
+            // Viewer rolü ve strateji çalıştırmayan (api) süreçlerde hata döner; kontroller gizli kalır.
+            cent.rpc('get_strategy', {}).then((res) => setStrategy(res.data)).catch(() => setStrategy(null));
         });
 
         cent.on('disconnected', (ctx) => {
@@ -56,18 +109,30 @@ const TradingMonitor = () => {
             addLog(`❌ Bağlantı koptu: ${ctx.reason}`, 'error');
         });
 
-        // 2. KANAL: KLINE (Fiyat Verisi)
-        const subKline = cent.newSubscription('kline');
+        // 2. KANAL: KLINE (Fiyat Verisi) - kline:<sembol>:<interval>
+        // Geçerli kanallar: GET /api/v1/channels
+        const symbol = import.meta.env.VITE_SYMBOL || 'BTCUSDT';
+        const subKline = cent.newSubscription(`kline:${symbol}:1m`);
         subKline.on('publication', (ctx) => {
             const data = ctx.data;
+            // Birden fazla borsa aynı kanala yayın yapıyor, fiyatı Binance'ten gösteriyoruz.
+            if (data.exchange && data.exchange !== 'binance') return;
             if (data.close) {
                 setPrice(parseFloat(data.close).toFixed(2));
             }
         });
+        // Presence: Kanala biri katılınca/ayrılınca izleyici sayısını yenile
+        const refreshViewers = () => {
+            subKline.presenceStats().then((stats) => setViewers(stats.numClients)).catch(() => {});
+        };
+        subKline.on('subscribed', refreshViewers);
+        subKline.on('join', refreshViewers);
+        subKline.on('leave', refreshViewers);
         subKline.subscribe();
 
-        // 3. KANAL: SIGNALS (Al-Sat Sinyalleri)
-        const subSignals = cent.newSubscription('signals');
+        // 3. KANAL: SIGNALS (Al-Sat Sinyalleri) - signals:<strateji>
+        // Manuel emirler "signals:manual" kanalındadır; burada sadece RSI gösteriliyor.
+        const subSignals = cent.newSubscription('signals:rsi');
         subSignals.on('publication', (ctx) => {
             const signal = ctx.data;
                 // YENİ HALİ (BUNU YAPIŞTIR):
@@ -80,10 +145,17 @@ const TradingMonitor = () => {
 
             addLog(`🚀 SİNYAL GELDİ: ${action} - ${reason} (${symbol})`, logType);
         });
+        // Yeniden bağlanınca kaçırılan sinyaller sunucu geçmişinden (recovery) gelir
+        subSignals.on('subscribed', (ctx) => {
+            if (ctx.wasRecovering && !ctx.recovered) {
+                addLog('⚠️ Kopukluk sırasında gelen sinyallerin bir kısmı kurtarılamadı.', 'error');
+            }
+        });
         subSignals.subscribe();
 
-        // 4. KANAL: WALLET (Cüzdan Güncellemeleri)
-        const subWallet = cent.newSubscription('wallet');
+        // 4. KANAL: WALLET (Cüzdan Güncellemeleri) - kişiye özel, token'da bu hesap olmalı
+        const walletAccount = import.meta.env.VITE_WALLET_ACCOUNT || 'demo';
+        const subWallet = cent.newSubscription(`wallet:${walletAccount}`);
         subWallet.on('publication', (ctx) => {
             const data = ctx.data;
             setWallet({ 
@@ -93,6 +165,12 @@ const TradingMonitor = () => {
             // Hangi bakiyenin değiştiğini loglayalım
             addLog(`💰 Cüzdan Güncellendi: ${parseFloat(data.usdt).toFixed(2)}$ / ${parseFloat(data.btc).toFixed(5)} BTC`, 'success');
         });
+        // Abone olunca sunucu cüzdanın son halini (snapshot) gönderir
+        subWallet.on('subscribed', (ctx) => {
+            if (ctx.data) {
+                setWallet({ usdt: parseFloat(ctx.data.usdt), btc: parseFloat(ctx.data.btc) });
+            }
+        });
         subWallet.subscribe();
 
         // Bağlan
@@ -118,6 +196,9 @@ const TradingMonitor = () => {
                 <div className={`flex items-center gap-2 px-4 py-1 rounded-full border ${isConnected ? 'border-green-900 bg-green-900/20 text-green-400' : 'border-red-900 bg-red-900/20 text-red-500'}`}>
                     <span className={`w-2 h-2 rounded-full ${isConnected ? 'bg-green-500 animate-pulse' : 'bg-red-500'}`}></span>
                     <span className="text-xs font-semibold">{status}</span>
+                    {isConnected && viewers > 0 && (
+                        <span className="text-xs text-gray-500">· 👀 {viewers}</span>
+                    )}
                 </div>
             </div>
 
@@ -159,6 +240,29 @@ const TradingMonitor = () => {
                 </div>
             </div>
 
+            {/* STRATEJİ KONTROLLERİ (RPC, trader rolü) */}
+            {strategy && (
+                <div className="w-full max-w-4xl flex flex-wrap items-center justify-between gap-4 mb-8 text-xs">
+                    <span className="text-gray-500">
+                        RSI({strategy.params.rsi_period}) {strategy.params.oversold}/{strategy.params.overbought} —{' '}
+                        <span className={strategy.running ? 'text-green-400' : 'text-red-500'}>
+                            {strategy.running ? 'ÇALIŞIYOR' : 'DURDURULDU'}
+                        </span>
+                    </span>
+                    <div className="flex gap-2">
+                        <button onClick={toggleStrategy} className="px-3 py-1 rounded border border-gray-700 hover:border-gray-500">
+                            {strategy.running ? 'Durdur' : 'Başlat'}
+                        </button>
+                        <button onClick={() => manualOrder('BUY')} className="px-3 py-1 rounded border border-green-900 text-green-400 hover:border-green-500">
+                            Manuel AL
+                        </button>
+                        <button onClick={() => manualOrder('SELL')} className="px-3 py-1 rounded border border-red-900 text-red-400 hover:border-red-500">
+                            Manuel SAT
+                        </button>
+                    </div>
+                </div>
+            )}
+
             {/* LOG PANELİ (TERMINAL GÖRÜNÜMÜ) */}
             <div className="w-full max-w-4xl flex-1 min-h-[300px] bg-[#050505] border border-gray-800 rounded-xl overflow-hidden shadow-2xl flex flex-col">
                 {/* Terminal Başlığı */}